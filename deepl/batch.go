@@ -0,0 +1,205 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// MaxTextsPerRequest is the maximum number of texts per translate request.
+	MaxTextsPerRequest = 50
+	// MaxRequestBodySize is the maximum size of a translate request body in bytes.
+	MaxRequestBodySize = 128 * 1024
+
+	defaultMaxConcurrency = 4
+)
+
+// ErrTextTooLarge is reported for texts that exceed the request body size
+// limit on their own and can therefore not be translated.
+var ErrTextTooLarge = errors.New("text exceeds maximum request body size")
+
+// BatchError is returned by TranslateTextBatch if some of the texts could not
+// be translated.
+type BatchError struct {
+	// The errors keyed by the index of the affected text
+	Errors map[int]error
+	// The total number of texts
+	Total int
+}
+
+func (e *BatchError) Error() string {
+	indices := e.Indices()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to translate %d of %d texts", len(indices), e.Total)
+	if len(indices) > 0 {
+		fmt.Fprintf(&b, ": text %d: %v", indices[0], e.Errors[indices[0]])
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the underlying errors ordered by index.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, i := range e.Indices() {
+		errs = append(errs, e.Errors[i])
+	}
+	return errs
+}
+
+// Indices returns the sorted indices of the texts that failed.
+func (e *BatchError) Indices() []int {
+	indices := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// WithMaxConcurrency sets the maximum number of concurrent requests issued by
+// methods that split their work into multiple requests.
+func WithMaxConcurrency(n int) TranslatorOption {
+	return func(t *Translator) error {
+		if n < 1 {
+			return errors.New("maximum concurrency must be positive")
+		}
+		t.maxConcurrency = n
+		return nil
+	}
+}
+
+// TranslateTextBatch translates an arbitrary number of texts into the
+// specified target language.
+//
+// The texts are split into requests that comply with the API limits on the
+// number of texts and request body size, which are sent concurrently. The
+// translations are returned in the order of the given texts. If some of the
+// requests fail, the translations of the remaining texts are returned along
+// with a *BatchError.
+func (t *Translator) TranslateTextBatch(ctx context.Context, text []string, targetLang string, opts ...TranslateOption) ([]Translation, error) {
+	return t.translateTextBatch(ctx, make(chan struct{}, t.maxConcurrency), text, targetLang, opts...)
+}
+
+// translateTextBatch is like TranslateTextBatch but acquires the given
+// semaphore for every request, which may be shared with other batches
+func (t *Translator) translateTextBatch(ctx context.Context, sem chan struct{}, text []string, targetLang string, opts ...TranslateOption) ([]Translation, error) {
+	batches, errs, err := splitTextBatches(text, targetLang, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// fail early instead of once per batch
+	if t.strict {
+		if err := t.ValidateTranslateOptions(ctx, targetLang, opts...); err != nil {
+			return nil, err
+		}
+	}
+
+	translations := make([]Translation, len(text))
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, batch := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			for _, i := range batch {
+				errs[i] = ctx.Err()
+			}
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(batch []int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			texts := make([]string, 0, len(batch))
+			for _, i := range batch {
+				texts = append(texts, text[i])
+			}
+
+			ts, err := t.TranslateTextContext(ctx, texts, targetLang, opts...)
+			if err == nil && len(ts) != len(texts) {
+				err = fmt.Errorf("unexpected number of translations: got %d, want %d", len(ts), len(texts))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for j, i := range batch {
+				if err != nil {
+					errs[i] = err
+				} else {
+					translations[i] = ts[j]
+				}
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return translations, &BatchError{Errors: errs, Total: len(text)}
+	}
+
+	return translations, nil
+}
+
+// splitTextBatches groups the indices of the given texts into batches that
+// comply with the API request limits. Texts that are too large to be sent at
+// all are reported in the returned error map.
+func splitTextBatches(text []string, targetLang string, opts ...TranslateOption) ([][]int, map[int]error, error) {
+	data := translateRequest{
+		Text:       []string{},
+		TargetLang: targetLang,
+	}
+	if err := data.TranslateOptions.Gather(opts...); err != nil {
+		return nil, nil, fmt.Errorf("error setting translate option: %w", err)
+	}
+	data.TranslateOptions.resolveGlossary(targetLang)
+
+	base, err := encodeJSON(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding request data: %w", err)
+	}
+
+	var (
+		batches [][]int
+		batch   []int
+		size    = len(base)
+		errs    = make(map[int]error)
+	)
+	for i, s := range text {
+		encoded, err := encodeJSON(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error encoding request data: %w", err)
+		}
+		n := len(encoded) + 1 // separating comma
+
+		if len(base)+n > MaxRequestBodySize {
+			errs[i] = ErrTextTooLarge
+			continue
+		}
+
+		if len(batch) == MaxTextsPerRequest || size+n > MaxRequestBodySize {
+			batches = append(batches, batch)
+			batch, size = nil, len(base)
+		}
+		batch = append(batch, i)
+		size += n
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches, errs, nil
+}
@@ -0,0 +1,137 @@
+package deepl_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+// sentTexts returns the texts of the given translate request bodies
+func sentTexts(t *testing.T, bodies [][]byte) [][]string {
+	t.Helper()
+
+	texts := make([][]string, 0, len(bodies))
+	for _, body := range bodies {
+		if len(body) > deepl.MaxRequestBodySize {
+			t.Errorf("got request body of %d bytes, want at most %d", len(body), deepl.MaxRequestBodySize)
+		}
+
+		var data struct {
+			Text []string `json:"text"`
+		}
+		if err := json.Unmarshal(body, &data); err != nil {
+			t.Fatalf("error decoding request body: %v", err)
+		}
+		texts = append(texts, data.Text)
+	}
+	return texts
+}
+
+func TestTranslateTextBatch(t *testing.T) {
+	large := strings.Repeat("x", 40*1024)
+	// escaped in JSON by default, 6 bytes each
+	html := strings.Repeat("<", 60*1024)
+
+	tests := []struct {
+		name  string
+		texts []string
+		// the number of texts per request
+		want []int
+	}{
+		{
+			name:  "text limit",
+			texts: make([]string, 2*deepl.MaxTextsPerRequest+20),
+			want:  []int{deepl.MaxTextsPerRequest, deepl.MaxTextsPerRequest, 20},
+		},
+		{
+			name:  "body size limit",
+			texts: []string{large, large, large, large},
+			want:  []int{3, 1},
+		},
+		{
+			name:  "html characters",
+			texts: []string{html, html, html},
+			want:  []int{2, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := deepltest.NewServer()
+			defer srv.Close()
+
+			client := &recordingClient{client: srv.Client()}
+			translator := newTestTranslator(t, srv, deepl.WithHTTPClient(client), deepl.WithMaxConcurrency(2))
+
+			texts := make([]string, len(tt.texts))
+			for i, text := range tt.texts {
+				texts[i] = fmt.Sprintf("%d %s", i, text)
+			}
+
+			translations, err := translator.TranslateTextBatch(context.Background(), texts, "DE")
+			if err != nil {
+				t.Fatalf("TranslateTextBatch: %v", err)
+			}
+
+			// the translations are in input order across requests
+			if len(translations) != len(texts) {
+				t.Fatalf("got %d translations, want %d", len(translations), len(texts))
+			}
+			for i, text := range texts {
+				if want := deepltest.Translate(text, "DE"); translations[i].Text != want {
+					t.Errorf("got translation %d %.20q, want %.20q", i, translations[i].Text, want)
+				}
+			}
+
+			sent := sentTexts(t, client.attempts("/v2/translate"))
+			counts := make([]int, len(sent))
+			for i, ts := range sent {
+				counts[i] = len(ts)
+			}
+			// requests are sent concurrently, so their order is arbitrary
+			sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+			if fmt.Sprint(counts) != fmt.Sprint(tt.want) {
+				t.Errorf("got requests with %v texts, want %v", counts, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateTextBatchTextTooLarge(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	client := &recordingClient{client: srv.Client()}
+	translator := newTestTranslator(t, srv, deepl.WithHTTPClient(client))
+
+	texts := []string{"Hello", strings.Repeat("x", deepl.MaxRequestBodySize), "World"}
+	translations, err := translator.TranslateTextBatch(context.Background(), texts, "DE")
+
+	var batchErr *deepl.BatchError
+	if !errors.As(err, &batchErr) || !errors.Is(err, deepl.ErrTextTooLarge) {
+		t.Fatalf("got error %v, want *BatchError matching ErrTextTooLarge", err)
+	}
+	if indices := batchErr.Indices(); len(indices) != 1 || indices[0] != 1 {
+		t.Errorf("got failed indices %v, want [1]", indices)
+	}
+	if batchErr.Total != 3 {
+		t.Errorf("got %d total texts, want 3", batchErr.Total)
+	}
+
+	// the remaining texts are translated
+	for _, i := range []int{0, 2} {
+		if want := deepltest.Translate(texts[i], "DE"); translations[i].Text != want {
+			t.Errorf("got translation %q, want %q", translations[i].Text, want)
+		}
+	}
+	if sent := sentTexts(t, client.attempts("/v2/translate")); len(sent) != 1 || len(sent[0]) != 2 {
+		t.Errorf("got requests with texts %v, want one with 2 texts", sent)
+	}
+}
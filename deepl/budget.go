@@ -0,0 +1,219 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBudgetRefreshInterval = 5 * time.Minute
+)
+
+// ErrBudgetExceeded matches any *BudgetError using errors.Is.
+var ErrBudgetExceeded = errors.New("character budget exceeded")
+
+// BudgetError is returned if a request is refused because it would exceed the
+// character budget set using WithBudget.
+type BudgetError struct {
+	// The number of characters of the refused request, 0 for documents
+	Requested int
+	// The number of characters used by the account
+	Used int
+	// The maximum number of characters allowed by the budget
+	Limit int
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("%v: %d characters requested, %d of %d used", ErrBudgetExceeded, e.Requested, e.Used, e.Limit)
+}
+
+// Is reports whether target is ErrBudgetExceeded.
+func (e *BudgetError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// Budget configures the character budget of the account.
+type Budget struct {
+	// The maximum character count of the account, 0 means no limit
+	MaxCharacters int
+	// The maximum percentage of the character limit of the account, 0 means
+	// no limit
+	MaxPercent float64
+	// How often the usage of the account is retrieved, defaults to 5 minutes
+	RefreshInterval time.Duration
+}
+
+// WithBudget refuses requests that would exceed the given character budget
+// with a *BudgetError.
+//
+// The character count of the account is retrieved periodically and the
+// characters of text translations sent in between are counted locally, so
+// usage by other clients is only taken into account as of the last refresh.
+// Requests still in flight during a refresh remain counted, even if the
+// retrieved character count already includes them, so the budget errs on the
+// side of refusing requests.
+//
+// Text translations are refused if their characters would exceed the budget,
+// document uploads are refused once the budget is used up, as their number of
+// characters is only known after the translation. Their billed characters are
+// counted once their status is reported as done, documents that are never
+// polled to completion are only taken into account by the next refresh.
+func WithBudget(b Budget) TranslatorOption {
+	return func(t *Translator) error {
+		if b.MaxCharacters < 0 || b.MaxPercent < 0 || b.MaxPercent > 100 || b.RefreshInterval < 0 {
+			return errors.New("invalid budget")
+		}
+		if b.MaxCharacters == 0 && b.MaxPercent == 0 {
+			t.budget = nil
+			return nil
+		}
+
+		interval := b.RefreshInterval
+		if interval == 0 {
+			interval = defaultBudgetRefreshInterval
+		}
+
+		t.budget = &budgetGuard{
+			maxCharacters:   b.MaxCharacters,
+			maxPercent:      b.MaxPercent,
+			refreshInterval: interval,
+		}
+		return nil
+	}
+}
+
+// budgetGuard keeps track of the character count of the account, a nil guard
+// does not refuse anything
+type budgetGuard struct {
+	maxCharacters   int
+	maxPercent      float64
+	refreshInterval time.Duration
+
+	// refreshMu serializes usage retrieval
+	refreshMu sync.Mutex
+
+	mu    sync.Mutex
+	usage cachedValue[Usage]
+	// characters of requests in flight
+	inflight int
+	// characters of completed requests since the usage was retrieved
+	pending int
+}
+
+// reserveBudget counts the given number of characters towards the budget or
+// returns a *BudgetError if they would exceed it. A zero number of characters
+// is only refused if the budget is used up.
+func (t *Translator) reserveBudget(ctx context.Context, n int) error {
+	b := t.budget
+	if b == nil {
+		return nil
+	}
+
+	if err := t.refreshUsage(ctx); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	limit, ok := b.limit()
+	if !ok {
+		return nil
+	}
+
+	used := b.usage.value.CharacterCount + b.pending + b.inflight
+	if used+n > limit || (n == 0 && used >= limit) {
+		return &BudgetError{Requested: n, Used: used, Limit: limit}
+	}
+
+	b.inflight += n
+	return nil
+}
+
+// refreshUsage retrieves the usage of the account if it is not fresh. If this
+// fails, the previous usage is kept until the next refresh.
+func (t *Translator) refreshUsage(ctx context.Context) error {
+	b := t.budget
+
+	b.refreshMu.Lock()
+	defer b.refreshMu.Unlock()
+
+	b.mu.Lock()
+	fresh, known := b.usage.fresh(b.refreshInterval), !b.usage.fetched.IsZero()
+	// only requests completed before are assumed to be included in the usage
+	completed := b.pending
+	b.mu.Unlock()
+	if fresh {
+		return nil
+	}
+
+	usage, err := t.GetUsageContext(ctx)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err != nil {
+		if !known {
+			return fmt.Errorf("error retrieving usage: %w", err)
+		}
+		b.usage.fetched = time.Now()
+		return nil
+	}
+
+	b.usage = cachedValue[Usage]{value: *usage, fetched: time.Now()}
+	b.pending -= completed
+	return nil
+}
+
+// limit returns the maximum character count allowed by the budget, b.mu must
+// be held
+func (b *budgetGuard) limit() (int, bool) {
+	limit := b.maxCharacters
+	if b.maxPercent > 0 && b.usage.value.CharacterLimit > 0 {
+		l := int(float64(b.usage.value.CharacterLimit) * b.maxPercent / 100)
+		if limit == 0 || l < limit {
+			limit = l
+		}
+	}
+	return limit, limit > 0
+}
+
+// commit counts the reserved characters of a completed request as used
+func (b *budgetGuard) commit(n int) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inflight -= n
+	b.pending += n
+}
+
+// release returns the reserved characters of a request that was not billed
+func (b *budgetGuard) release(n int) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inflight -= n
+}
+
+// documentDone counts the billed characters of a translated document
+func (b *budgetGuard) documentDone(billed int) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending += billed
+}
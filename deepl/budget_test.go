@@ -0,0 +1,142 @@
+package deepl_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+func checkBudgetError(t *testing.T, err error, want deepl.BudgetError) {
+	t.Helper()
+
+	var budgetErr *deepl.BudgetError
+	if !errors.As(err, &budgetErr) || !errors.Is(err, deepl.ErrBudgetExceeded) {
+		t.Fatalf("got error %v, want *BudgetError", err)
+	}
+	if *budgetErr != want {
+		t.Errorf("got %+v, want %+v", *budgetErr, want)
+	}
+}
+
+func TestBudgetMaxCharacters(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	srv.SetCharacterCount(90)
+
+	translator := newTestTranslator(t, srv, deepl.WithBudget(deepl.Budget{MaxCharacters: 100}))
+
+	if _, err := translator.TranslateText([]string{"Hello"}, "DE"); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	_, err := translator.TranslateText([]string{"Hello, World!"}, "DE")
+	checkBudgetError(t, err, deepl.BudgetError{Requested: 13, Used: 95, Limit: 100})
+
+	if n := srv.Usage().CharacterCount; n != 95 {
+		t.Errorf("got character count %d, want 95", n)
+	}
+}
+
+func TestBudgetMaxPercent(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithCharacterLimit(1000))
+	defer srv.Close()
+	srv.SetCharacterCount(495)
+
+	translator := newTestTranslator(t, srv, deepl.WithBudget(deepl.Budget{MaxPercent: 50}))
+
+	if _, err := translator.TranslateText([]string{"Hello"}, "DE"); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	_, err := translator.TranslateText([]string{"x"}, "DE")
+	checkBudgetError(t, err, deepl.BudgetError{Requested: 1, Used: 500, Limit: 500})
+}
+
+func TestBudgetReleasedAfterFailure(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:     deepltest.FaultServiceUnavailable,
+		Endpoint: "v2/translate",
+		Nth:      1,
+	}))
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv, deepl.WithoutRetries(), deepl.WithBudget(deepl.Budget{MaxCharacters: 10}))
+
+	if _, err := translator.TranslateText([]string{"0123456789"}, "DE"); !errors.Is(err, deepl.ErrorStatusInternalServerError) {
+		t.Fatalf("got error %v, want server error", err)
+	}
+
+	// the characters of the failed request do not count towards the budget
+	if _, err := translator.TranslateText([]string{"0123456789"}, "DE"); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+}
+
+func TestBudgetCountsDocumentsOnce(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithDocumentPolls(0))
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv, deepl.WithBudget(deepl.Budget{MaxCharacters: 8}))
+	ctx := context.Background()
+
+	doc, err := translator.TranslateDocumentUploadReader(ctx, strings.NewReader("Hello"), "hello.txt", "", "DE")
+	if err != nil {
+		t.Fatalf("TranslateDocumentUploadReader: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		status, err := translator.TranslateDocumentStatusContext(ctx, doc.DocumentId, doc.DocumentKey)
+		if err != nil {
+			t.Fatalf("TranslateDocumentStatusContext: %v", err)
+		}
+		if !status.Done() {
+			t.Fatalf("got status %q, want done", status.Status)
+		}
+	}
+
+	if _, err := translator.TranslateText([]string{"abc"}, "DE"); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	_, err = translator.TranslateText([]string{"a"}, "DE")
+	checkBudgetError(t, err, deepl.BudgetError{Requested: 1, Used: 8, Limit: 8})
+
+	// the budget is used up, so documents are refused
+	_, err = translator.TranslateDocumentUploadReader(ctx, strings.NewReader("a"), "a.txt", "", "DE")
+	checkBudgetError(t, err, deepl.BudgetError{Requested: 0, Used: 8, Limit: 8})
+}
+
+func TestBudgetKeepsInflightAcrossRefresh(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:     deepltest.FaultSlow,
+		Endpoint: "v2/translate",
+		Percent:  100,
+		Delay:    500 * time.Millisecond,
+	}))
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv, deepl.WithBudget(deepl.Budget{
+		MaxCharacters:   10,
+		RefreshInterval: time.Nanosecond,
+	}))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := translator.TranslateText([]string{"01234567"}, "DE")
+		done <- err
+	}()
+	// let the first request be sent, the server counts it only once done
+	time.Sleep(100 * time.Millisecond)
+
+	// the usage is refreshed, but the first request is still counted
+	_, err := translator.TranslateText([]string{"012"}, "DE")
+	checkBudgetError(t, err, deepl.BudgetError{Requested: 3, Used: 8, Limit: 10})
+
+	if err := <-done; err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+}
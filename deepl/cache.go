@@ -0,0 +1,302 @@
+package deepl
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache stores translations of single texts keyed by an opaque string that
+// covers the text, target language and translate options.
+//
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached translation for the given key, if present
+	Get(key string) (Translation, bool)
+	// Set stores the translation for the given key
+	Set(key string, value Translation)
+}
+
+// CacheStats holds the number of cache lookups that were hits or misses.
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// WithCache enables caching of text translations
+func WithCache(c Cache) TranslatorOption {
+	return func(t *Translator) error {
+		t.cache = c
+		return nil
+	}
+}
+
+// CacheStats returns the hit and miss counts of the translation cache.
+func (t *Translator) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   t.cacheHits.Load(),
+		Misses: t.cacheMisses.Load(),
+	}
+}
+
+// translateTextCached looks up the texts of the given request in the cache and
+// only requests translations for the missing ones
+func (t *Translator) translateTextCached(ctx context.Context, data translateRequest) ([]Translation, error) {
+	translations := make([]Translation, len(data.Text))
+
+	var (
+		keys    = make([]string, len(data.Text))
+		missing []int
+	)
+	for i, text := range data.Text {
+		key, err := cacheKey(text, data.TargetLang, data.TranslateOptions)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+
+		tr, ok := t.cache.Get(key)
+		if ok {
+			// cached translations are not billed again
+			tr.BilledCharacters = 0
+			translations[i] = tr
+			t.cacheHits.Add(1)
+		} else {
+			missing = append(missing, i)
+			t.cacheMisses.Add(1)
+		}
+		if t.metrics != nil {
+			t.metrics.CacheLookup(ok)
+		}
+	}
+
+	if len(missing) == 0 {
+		return translations, nil
+	}
+
+	req := data
+	req.Text = make([]string, 0, len(missing))
+	for _, i := range missing {
+		req.Text = append(req.Text, data.Text[i])
+	}
+
+	ts, err := t.translateText(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(ts) != len(req.Text) {
+		return nil, fmt.Errorf("unexpected number of translations: got %d, want %d", len(ts), len(req.Text))
+	}
+
+	for j, i := range missing {
+		translations[i] = ts[j]
+		t.cache.Set(keys[i], ts[j])
+	}
+
+	return translations, nil
+}
+
+// cacheKey derives the cache key of a single text translation
+func cacheKey(text string, targetLang string, options TranslateOptions) (string, error) {
+	data := translateRequest{
+		Text:             []string{text},
+		TargetLang:       targetLang,
+		TranslateOptions: options,
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("error encoding cache key: %w", err)
+	}
+
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+/*
+ *  LRU
+ */
+
+// LRUCache is an in-memory cache that evicts the least recently used entries
+// once its capacity is reached.
+type LRUCache struct {
+	capacity int
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key     string
+	value   Translation
+	expires time.Time
+}
+
+// NewLRUCache creates an in-memory cache holding up to capacity entries. If
+// ttl is positive, entries expire after the given duration.
+func NewLRUCache(capacity int, ttl time.Duration) (*LRUCache, error) {
+	if capacity < 1 {
+		return nil, errors.New("cache capacity must be positive")
+	}
+
+	return &LRUCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}, nil
+}
+
+func (c *LRUCache) Get(key string) (Translation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return Translation{}, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return Translation{}, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (c *LRUCache) Set(key string, value Translation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{
+		key:     key,
+		value:   value,
+		expires: expires,
+	})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of cached entries, including expired ones that have
+// not been evicted yet.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+/*
+ *  FILE
+ */
+
+// FileCache is a cache that stores each entry as a file in a directory.
+//
+// Errors reading or writing entries are treated as cache misses.
+type FileCache struct {
+	dir string
+	ttl time.Duration
+}
+
+type fileCacheEntry struct {
+	Translation Translation `json:"translation"`
+	Expires     time.Time   `json:"expires"`
+}
+
+// NewFileCache creates a cache storing its entries in the given directory,
+// which is created if necessary. If ttl is positive, entries expire after the
+// given duration.
+func NewFileCache(dir string, ttl time.Duration) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating cache directory: %w", err)
+	}
+
+	return &FileCache{
+		dir: dir,
+		ttl: ttl,
+	}, nil
+}
+
+func (c *FileCache) Get(key string) (Translation, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return Translation{}, false
+	}
+
+	var entry fileCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Translation{}, false
+	}
+
+	if !entry.Expires.IsZero() && time.Now().After(entry.Expires) {
+		_ = os.Remove(c.path(key))
+		return Translation{}, false
+	}
+
+	return entry.Translation, true
+}
+
+func (c *FileCache) Set(key string, value Translation) {
+	entry := fileCacheEntry{
+		Translation: value,
+	}
+	if c.ttl > 0 {
+		entry.Expires = time.Now().Add(c.ttl)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// write to a temporary file first so readers never see partial entries
+	f, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return
+	}
+	if err := f.Close(); err != nil {
+		return
+	}
+
+	_ = os.Rename(f.Name(), c.path(key))
+}
+
+func (c *FileCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
@@ -0,0 +1,181 @@
+package deepl_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+func TestLRUCacheEviction(t *testing.T) {
+	c, err := deepl.NewLRUCache(2, 0)
+	if err != nil {
+		t.Fatalf("NewLRUCache: %v", err)
+	}
+
+	c.Set("a", deepl.Translation{Text: "A"})
+	c.Set("b", deepl.Translation{Text: "B"})
+
+	// using a makes b the least recently used entry
+	if tr, ok := c.Get("a"); !ok || tr.Text != "A" {
+		t.Fatalf("got %+v, %t for a", tr, ok)
+	}
+	c.Set("c", deepl.Translation{Text: "C"})
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+	if n := c.Len(); n != 2 {
+		t.Errorf("got %d entries, want 2", n)
+	}
+}
+
+func TestLRUCacheTTL(t *testing.T) {
+	c, err := deepl.NewLRUCache(2, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewLRUCache: %v", err)
+	}
+
+	c.Set("a", deepl.Translation{Text: "A"})
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a is missing before expiring")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("got expired entry")
+	}
+	if n := c.Len(); n != 0 {
+		t.Errorf("got %d entries, want the expired one to be removed", n)
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := deepl.NewFileCache(dir, 0)
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	c.Set("a", deepl.Translation{Text: "A", DetectedSourceLanguage: "EN"})
+	c.Set("a", deepl.Translation{Text: "AA", DetectedSourceLanguage: "EN"})
+
+	if tr, ok := c.Get("a"); !ok || tr.Text != "AA" || tr.DetectedSourceLanguage != "EN" {
+		t.Errorf("got %+v, %t for a", tr, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("got entry that was never set")
+	}
+
+	// entries are renamed into place, no temporary files remain
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "a.json" {
+		t.Errorf("got files %v, want only a.json", files)
+	}
+
+	// corrupt entries are misses
+	if err := os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"translation":`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error("got corrupt entry")
+	}
+}
+
+func TestFileCacheTTL(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := deepl.NewFileCache(dir, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	c.Set("a", deepl.Translation{Text: "A"})
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("got expired entry")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.json")); !os.IsNotExist(err) {
+		t.Errorf("expired entry was not removed: %v", err)
+	}
+}
+
+func TestTranslateTextCached(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	cache, err := deepl.NewLRUCache(10, 0)
+	if err != nil {
+		t.Fatalf("NewLRUCache: %v", err)
+	}
+	metrics := deepl.NewExpvarMetrics("")
+	client := &recordingClient{client: srv.Client()}
+	translator := newTestTranslator(t, srv,
+		deepl.WithHTTPClient(client),
+		deepl.WithCache(cache),
+		deepl.WithMetrics(metrics),
+	)
+
+	opt := deepl.WithShowBilledCharacters(true)
+	if _, err := translator.TranslateText([]string{"Hello", "World"}, "DE", opt); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	translations, err := translator.TranslateText([]string{"Hello", "Again"}, "DE", opt)
+	if err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	for i, text := range []string{"Hello", "Again"} {
+		if want := deepltest.Translate(text, "DE"); translations[i].Text != want {
+			t.Errorf("got translation %q, want %q", translations[i].Text, want)
+		}
+	}
+	// cached translations are not billed again
+	if billed := translations[0].BilledCharacters; billed != 0 {
+		t.Errorf("got %d billed characters for cached translation, want 0", billed)
+	}
+	if billed := translations[1].BilledCharacters; billed != 5 {
+		t.Errorf("got %d billed characters, want 5", billed)
+	}
+
+	// only the missing text is sent
+	bodies := client.attempts("/v2/translate")
+	if len(bodies) != 2 {
+		t.Fatalf("got %d requests, want 2", len(bodies))
+	}
+	if bytes.Contains(bodies[1], []byte("Hello")) || !bytes.Contains(bodies[1], []byte("Again")) {
+		t.Errorf("got request %s, want only the missing text", bodies[1])
+	}
+	if usage := srv.Usage(); usage.CharacterCount != 15 {
+		t.Errorf("got character count %d, want 15", usage.CharacterCount)
+	}
+
+	// a fully cached request is not sent at all
+	if _, err := translator.TranslateText([]string{"World"}, "DE", opt); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	if n := len(client.attempts("/v2/translate")); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+
+	if stats := translator.CacheStats(); stats != (deepl.CacheStats{Hits: 2, Misses: 3}) {
+		t.Errorf("got cache stats %+v, want 2 hits and 3 misses", stats)
+	}
+	if hits := metrics.Var().Get("cache_hits").String(); hits != "2" {
+		t.Errorf("got %s cache hits in metrics, want 2", hits)
+	}
+}
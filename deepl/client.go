@@ -1,36 +1,156 @@
 package deepl
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type HTTPClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
-func httpError(statusCode int) error {
-	var statusText string
-	switch statusCode {
-	case 456:
-		statusText = "Quota exceeded. The character limit has been reached."
-	default:
-		statusText = http.StatusText(statusCode)
+// Sentinel errors that can be matched against an *APIError using errors.Is.
+var (
+	ErrAuthFailed      = errors.New("authorization failed")
+	ErrNotFound        = errors.New("resource not found")
+	ErrQuotaExceeded   = errors.New("quota exceeded")
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// Deprecated: Use ErrTooManyRequests instead.
+var ErrorStatusTooManyRequests = ErrTooManyRequests
+
+// ErrorStatusInternalServerError matches any *APIError with a 5xx status code.
+var ErrorStatusInternalServerError = errors.New("internal server error")
+
+// StatusQuotaExceeded is the non-standard status code DeepL uses to signal
+// that the character limit has been reached.
+const StatusQuotaExceeded = 456
+
+// maxErrorBodySize limits how much of an error response body is read.
+const maxErrorBodySize = 64 * 1024
+
+// APIError is returned when the DeepL API responds with an unexpected status
+// code.
+type APIError struct {
+	// The HTTP status code of the response
+	StatusCode int
+	// The API endpoint that was called, e.g. `v2/translate`
+	Endpoint string
+	// The error message returned by the API, if any
+	Message string
+	// Additional details about the error returned by the API, if any
+	Detail string
+	// The response headers, e.g. containing `Retry-After`
+	Header http.Header
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s: %d - %s", e.Endpoint, e.StatusCode, statusText(e.StatusCode))
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.Detail != "" {
+		fmt.Fprintf(&b, " (%s)", e.Detail)
+	}
+
+	return b.String()
+}
+
+// Is reports whether the error matches one of the package's sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrAuthFailed:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrQuotaExceeded:
+		return e.StatusCode == StatusQuotaExceeded
+	case ErrTooManyRequests:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrorStatusInternalServerError:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// RetryAfter returns the delay requested by the server via the `Retry-After`
+// response header, if present.
+func (e *APIError) RetryAfter() (time.Duration, bool) {
+	if e.Header == nil {
+		return 0, false
 	}
 
-	return fmt.Errorf("%d - %s", statusCode, statusText)
+	value := e.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
 }
 
-var ErrorStatusTooManyRequests = errors.New(httpError(http.StatusTooManyRequests).Error())
-var ErrorStatusInternalServerError = errors.New(httpError(http.StatusInternalServerError).Error())
+// httpError creates an *APIError from the given response, consuming and
+// closing its body.
+func httpError(endpoint string, res *http.Response) error {
+	defer res.Body.Close()
+
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		Endpoint:   endpoint,
+		Header:     res.Header,
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+	if err != nil || len(body) == 0 {
+		return apiErr
+	}
 
-func retriableHTTPError(statusCode int) error {
-	switch {
-	case statusCode == http.StatusTooManyRequests:
-		return ErrorStatusTooManyRequests
-	case statusCode >= http.StatusInternalServerError:
-		return ErrorStatusInternalServerError
+	var data struct {
+		Message string `json:"message"`
+		Detail  string `json:"detail"`
 	}
-	return nil
+	if err := json.Unmarshal(body, &data); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+	} else {
+		apiErr.Message = data.Message
+		apiErr.Detail = data.Detail
+	}
+
+	return apiErr
+}
+
+func statusText(statusCode int) string {
+	switch statusCode {
+	case StatusQuotaExceeded:
+		return "Quota exceeded. The character limit has been reached."
+	default:
+		return http.StatusText(statusCode)
+	}
+}
+
+func isRetriableStatusCode(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
 }
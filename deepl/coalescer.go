@@ -0,0 +1,228 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCoalesceWindow = 10 * time.Millisecond
+)
+
+// Coalescer collects concurrent single text translations that share the same
+// target language and options and sends them as one request.
+type Coalescer struct {
+	translator   *Translator
+	window       time.Duration
+	maxBatchSize int
+
+	mu      sync.Mutex
+	pending map[string]*coalescedBatch
+}
+
+// CoalescerOption is a functional option for configuring the Coalescer
+type CoalescerOption func(*Coalescer) error
+
+// WithCoalesceWindow sets how long a batch collects texts before it is sent
+func WithCoalesceWindow(d time.Duration) CoalescerOption {
+	return func(c *Coalescer) error {
+		if d < 0 {
+			return errors.New("coalesce window must be non-negative")
+		}
+		c.window = d
+		return nil
+	}
+}
+
+// WithMaxBatchSize sets the number of texts at which a batch is sent
+// immediately, it must not exceed MaxTextsPerRequest
+func WithMaxBatchSize(n int) CoalescerOption {
+	return func(c *Coalescer) error {
+		if n < 1 || n > MaxTextsPerRequest {
+			return fmt.Errorf("max batch size must be between 1 and %d", MaxTextsPerRequest)
+		}
+		c.maxBatchSize = n
+		return nil
+	}
+}
+
+// NewCoalescer creates a new coalescer sending its requests using the given
+// translator
+func NewCoalescer(t *Translator, opts ...CoalescerOption) (*Coalescer, error) {
+	if t == nil {
+		return nil, errors.New("translator must not be nil")
+	}
+
+	c := &Coalescer{
+		translator:   t,
+		window:       defaultCoalesceWindow,
+		maxBatchSize: MaxTextsPerRequest,
+
+		pending: make(map[string]*coalescedBatch),
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Translate translates the given text into the specified target language,
+// sharing the request with concurrent calls using the same parameters.
+func (c *Coalescer) Translate(ctx context.Context, text string, targetLang string, opts ...TranslateOption) (*Translation, error) {
+	data := translateRequest{
+		TargetLang: targetLang,
+	}
+	if err := data.TranslateOptions.Gather(opts...); err != nil {
+		return nil, fmt.Errorf("error setting translate option: %w", err)
+	}
+	data.TranslateOptions.resolveGlossary(targetLang)
+
+	encoded, err := encodeJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request data: %w", err)
+	}
+	key := string(encoded)
+
+	encodedText, err := encodeJSON(text)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request data: %w", err)
+	}
+	size := len(encodedText) + 1 // separating comma
+	if len(encoded)+size > MaxRequestBodySize {
+		return nil, ErrTextTooLarge
+	}
+
+	w := &coalescedWaiter{
+		done: make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	b, ok := c.pending[key]
+	if ok && b.size+size > MaxRequestBodySize {
+		c.flushLocked(key)
+		ok = false
+	}
+	if !ok {
+		b = newCoalescedBatch(key, targetLang, data.TranslateOptions, len(encoded))
+		c.pending[key] = b
+		b.timer = time.AfterFunc(c.window, func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if c.pending[key] == b {
+				c.flushLocked(key)
+			}
+		})
+	}
+	w.index = len(b.texts)
+	b.texts = append(b.texts, text)
+	b.waiters = append(b.waiters, w)
+	b.size += size
+	if len(b.texts) >= c.maxBatchSize {
+		c.flushLocked(key)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return w.translation, w.err
+	case <-ctx.Done():
+		c.abandon(b)
+		return nil, ctx.Err()
+	}
+}
+
+// abandon records that a waiter of the given batch has given up and cancels
+// the batch once all of them have
+func (c *Coalescer) abandon(b *coalescedBatch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b.abandoned++
+	if b.abandoned < len(b.waiters) {
+		return
+	}
+
+	if c.pending[b.key] == b {
+		delete(c.pending, b.key)
+		b.timer.Stop()
+	}
+	b.cancel()
+}
+
+// flushLocked sends the pending batch with the given key, c.mu must be held
+func (c *Coalescer) flushLocked(key string) {
+	b := c.pending[key]
+	delete(c.pending, key)
+	b.timer.Stop()
+
+	go b.send(c.translator)
+}
+
+type coalescedWaiter struct {
+	index int
+	done  chan struct{}
+
+	translation *Translation
+	err         error
+}
+
+type coalescedBatch struct {
+	key        string
+	targetLang string
+	options    TranslateOptions
+
+	texts   []string
+	waiters []*coalescedWaiter
+	size    int
+	timer   *time.Timer
+
+	// the batch request is cancelled once all waiters have given up
+	ctx       context.Context
+	cancel    context.CancelFunc
+	abandoned int
+}
+
+func newCoalescedBatch(key string, targetLang string, options TranslateOptions, size int) *coalescedBatch {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &coalescedBatch{
+		key:        key,
+		targetLang: targetLang,
+		options:    options,
+		size:       size,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (b *coalescedBatch) send(t *Translator) {
+	defer b.cancel()
+
+	ts, err := t.TranslateTextContext(b.ctx, b.texts, b.targetLang, withTranslateOptions(b.options))
+	if err == nil && len(ts) != len(b.texts) {
+		err = fmt.Errorf("unexpected number of translations: got %d, want %d", len(ts), len(b.texts))
+	}
+
+	for _, w := range b.waiters {
+		if err != nil {
+			w.err = err
+		} else {
+			w.translation = &ts[w.index]
+		}
+		close(w.done)
+	}
+}
+
+// withTranslateOptions sets all options to the given already gathered ones
+func withTranslateOptions(options TranslateOptions) TranslateOption {
+	return func(o *TranslateOptions) error {
+		*o = options
+		return nil
+	}
+}
@@ -0,0 +1,215 @@
+package deepl_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+func newTestCoalescer(t *testing.T, translator *deepl.Translator, opts ...deepl.CoalescerOption) *deepl.Coalescer {
+	t.Helper()
+
+	c, err := deepl.NewCoalescer(translator, opts...)
+	if err != nil {
+		t.Fatalf("failed to create coalescer: %v", err)
+	}
+	return c
+}
+
+type coalescedResult struct {
+	text        string
+	translation *deepl.Translation
+	err         error
+}
+
+// translateConcurrently translates each text in its own goroutine
+func translateConcurrently(ctx context.Context, c *deepl.Coalescer, texts []string) []coalescedResult {
+	results := make([]coalescedResult, len(texts))
+
+	var wg sync.WaitGroup
+	for i, text := range texts {
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			tr, err := c.Translate(ctx, text, "DE")
+			results[i] = coalescedResult{text: text, translation: tr, err: err}
+		}(i, text)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func checkCoalescedResults(t *testing.T, results []coalescedResult) {
+	t.Helper()
+
+	for _, r := range results {
+		if r.err != nil {
+			t.Errorf("Translate(%q): %v", r.text, r.err)
+			continue
+		}
+		if want := deepltest.Translate(r.text, "DE"); r.translation.Text != want {
+			t.Errorf("Translate(%q) = %q, want %q", r.text, r.translation.Text, want)
+		}
+	}
+}
+
+func TestCoalescerMergesConcurrentCalls(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	client := &recordingClient{client: srv.Client()}
+	c := newTestCoalescer(t, newTestTranslator(t, srv, deepl.WithHTTPClient(client)),
+		deepl.WithCoalesceWindow(100*time.Millisecond),
+	)
+
+	texts := make([]string, 10)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("Text %d", i)
+	}
+
+	results := translateConcurrently(context.Background(), c, texts)
+	checkCoalescedResults(t, results)
+
+	if n := len(client.attempts("/v2/translate")); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestCoalescerFlushesAtMaxBatchSize(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	client := &recordingClient{client: srv.Client()}
+	c := newTestCoalescer(t, newTestTranslator(t, srv, deepl.WithHTTPClient(client)),
+		deepl.WithCoalesceWindow(time.Hour),
+		deepl.WithMaxBatchSize(3),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results := translateConcurrently(ctx, c, []string{"one", "two", "three"})
+	checkCoalescedResults(t, results)
+
+	if n := len(client.attempts("/v2/translate")); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestCoalescerFlushesAtMaxRequestBodySize(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	client := &recordingClient{client: srv.Client()}
+	c := newTestCoalescer(t, newTestTranslator(t, srv, deepl.WithHTTPClient(client)),
+		deepl.WithCoalesceWindow(time.Hour),
+	)
+
+	first := strings.Repeat("a", deepl.MaxRequestBodySize/2+1)
+	second := strings.Repeat("b", deepl.MaxRequestBodySize/2+1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan coalescedResult, 1)
+	go func() {
+		tr, err := c.Translate(ctx, first, "DE")
+		done <- coalescedResult{text: first, translation: tr, err: err}
+	}()
+	// let the first text start a batch
+	time.Sleep(50 * time.Millisecond)
+
+	secondCtx, cancelSecond := context.WithCancel(ctx)
+	secondDone := make(chan error, 1)
+	go func() {
+		_, err := c.Translate(secondCtx, second, "DE")
+		secondDone <- err
+	}()
+
+	// the second text does not fit into the batch, which is sent right away
+	checkCoalescedResults(t, []coalescedResult{<-done})
+
+	bodies := client.attempts("/v2/translate")
+	if len(bodies) != 1 {
+		t.Fatalf("got %d requests, want 1", len(bodies))
+	}
+	if strings.Contains(string(bodies[0]), second) {
+		t.Error("first batch contains the second text")
+	}
+
+	// the second text waits for its own batch
+	cancelSecond()
+	if err := <-secondDone; !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+func TestCoalescerCancelsAbandonedRequest(t *testing.T) {
+	var (
+		started   = make(chan struct{})
+		cancelled = make(chan struct{})
+	)
+	client := deepl.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-req.Context().Done()
+		close(cancelled)
+		return nil, req.Context().Err()
+	})
+
+	translator, err := deepl.NewTranslator("auth-key", deepl.WithHTTPClient(client), deepl.WithoutRetries())
+	if err != nil {
+		t.Fatalf("failed to create translator: %v", err)
+	}
+	c := newTestCoalescer(t, translator, deepl.WithCoalesceWindow(time.Millisecond))
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel1()
+	defer cancel2()
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := c.Translate(ctx1, "one", "DE")
+		errs <- err
+	}()
+	go func() {
+		_, err := c.Translate(ctx2, "two", "DE")
+		errs <- err
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not sent")
+	}
+
+	// the request continues as long as one waiter is left
+	cancel1()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	select {
+	case <-cancelled:
+		t.Fatal("request was cancelled while a waiter is left")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel2()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not cancelled after all waiters gave up")
+	}
+}
@@ -0,0 +1,293 @@
+package deepltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+)
+
+// redacted replaces the values of sensitive headers in cassettes
+const redacted = "REDACTED"
+
+// ErrNoInteraction is returned by a Replayer if no recorded interaction
+// matches a request.
+var ErrNoInteraction = errors.New("no matching interaction recorded")
+
+// Cassette holds recorded request/response pairs.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and the response it received.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request as stored in a cassette.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body"`
+}
+
+// RecordedResponse is a response as stored in a cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body"`
+}
+
+// Body is a recorded message body, it is stored as text if it is valid UTF-8
+// and base64 encoded otherwise.
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(map[string]string{"text": string(b)})
+	}
+	return json.Marshal(map[string][]byte{"base64": b})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Text   *string `json:"text"`
+		Base64 []byte  `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Text != nil {
+		*b = Body(*v.Text)
+	} else {
+		*b = Body(v.Base64)
+	}
+	return nil
+}
+
+// LoadCassette reads a cassette from the given file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("error decoding cassette: %w", err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to the given file.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding cassette: %w", err)
+	}
+
+	// write to a temporary file first so readers never see partial cassettes
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+/*
+ *  RECORD
+ */
+
+// Recorder is a deepl.HTTPClient that records the requests it sends and the
+// responses it receives to a cassette file.
+//
+// The `Authorization` header is redacted in recorded requests.
+type Recorder struct {
+	client deepl.HTTPClient
+	path   string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a recorder that sends requests using the given client,
+// or http.DefaultClient if it is nil, and writes the cassette to path after
+// every interaction.
+func NewRecorder(path string, client deepl.HTTPClient) *Recorder {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Recorder{
+		client: client,
+		path:   path,
+	}
+}
+
+// Do sends the request and records the interaction.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	header := req.Header.Clone()
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", redacted)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.RawQuery,
+			Header: header,
+			Body:   reqBody,
+		},
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     res.Header.Clone(),
+			Body:       resBody,
+		},
+	})
+
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, fmt.Errorf("error saving cassette: %w", err)
+	}
+
+	return res, nil
+}
+
+/*
+ *  REPLAY
+ */
+
+// Replayer is a deepl.HTTPClient that answers requests with the responses
+// recorded in a cassette.
+//
+// Requests are matched by method, path and body. Each recorded interaction is
+// replayed once in the recorded order, after that the last matching one is
+// repeated.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewReplayer creates a replayer serving the cassette in the given file.
+func NewReplayer(path string) (*Replayer, error) {
+	c, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		interactions: c.Interactions,
+		replayed:     make([]bool, len(c.Interactions)),
+	}, nil
+}
+
+// Do returns the recorded response matching the request.
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	body = normalizeBody(req.Header.Get("Content-Type"), body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	for i, in := range r.interactions {
+		recorded := in.Request
+		if recorded.Method != req.Method || recorded.Path != req.URL.Path {
+			continue
+		}
+		if !bytes.Equal(normalizeBody(recorded.Header.Get("Content-Type"), recorded.Body), body) {
+			continue
+		}
+
+		match = i
+		if !r.replayed[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrNoInteraction)
+	}
+	r.replayed[match] = true
+
+	recorded := r.interactions[match].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// normalizeBody replaces the random boundary of multipart bodies so that
+// recorded uploads match
+func normalizeBody(contentType string, body []byte) []byte {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return body
+	}
+
+	return bytes.ReplaceAll(body, []byte(params["boundary"]), []byte("BOUNDARY"))
+}
@@ -0,0 +1,100 @@
+package deepltest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+)
+
+func TestCassetteRoundTrip(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := context.Background()
+
+	// record
+	recorder, err := deepl.NewTranslator(srv.AuthKey, deepl.WithServerURL(srv.URL), deepl.WithHTTPClient(NewRecorder(path, srv.Client())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := recorder.TranslateText([]string{"Hello", "World"}, "DE")
+	if err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	recordedDoc, err := recorder.TranslateDocumentUploadReader(ctx, strings.NewReader("Hello World"), "hello.txt", "text/plain", "DE")
+	if err != nil {
+		t.Fatalf("TranslateDocumentUploadReader: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), srv.AuthKey) {
+		t.Errorf("auth key %q was recorded:\n%s", srv.AuthKey, data)
+	}
+	if !strings.Contains(string(data), redacted) {
+		t.Errorf("authorization header was not recorded:\n%s", data)
+	}
+
+	// replay without a server
+	srv.Close()
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer: %v", err)
+	}
+	translator, err := deepl.NewTranslator("any-key", deepl.WithServerURL(srv.URL), deepl.WithHTTPClient(replayer), deepl.WithoutRetries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := translator.TranslateText([]string{"Hello", "World"}, "DE")
+	if err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	if len(replayed) != len(recorded) {
+		t.Fatalf("got %d translations, want %d", len(replayed), len(recorded))
+	}
+	for i := range recorded {
+		if replayed[i] != recorded[i] {
+			t.Errorf("got translation %+v, want %+v", replayed[i], recorded[i])
+		}
+	}
+
+	// the multipart boundary differs from the recorded one
+	replayedDoc, err := translator.TranslateDocumentUploadReader(ctx, strings.NewReader("Hello World"), "hello.txt", "text/plain", "DE")
+	if err != nil {
+		t.Fatalf("TranslateDocumentUploadReader: %v", err)
+	}
+	if *replayedDoc != *recordedDoc {
+		t.Errorf("got document %+v, want %+v", replayedDoc, recordedDoc)
+	}
+
+	// requests with a different body do not match
+	if _, err := translator.TranslateText([]string{"Goodbye"}, "DE"); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("got error %v, want ErrNoInteraction", err)
+	}
+	if _, err := translator.TranslateDocumentUploadReader(ctx, strings.NewReader("Goodbye"), "hello.txt", "text/plain", "DE"); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("got error %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestNormalizeBody(t *testing.T) {
+	body := []byte("--abc123\r\nContent-Disposition: form-data; name=\"target_lang\"\r\n\r\nDE\r\n--abc123--\r\n")
+	want := "--BOUNDARY\r\nContent-Disposition: form-data; name=\"target_lang\"\r\n\r\nDE\r\n--BOUNDARY--\r\n"
+
+	if got := normalizeBody("multipart/form-data; boundary=abc123", body); string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := normalizeBody("application/json", body); string(got) != string(body) {
+		t.Errorf("got %q, want unchanged body", got)
+	}
+}
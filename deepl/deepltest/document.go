@@ -0,0 +1,179 @@
+package deepltest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+)
+
+// maxDocumentSize limits the size of uploaded documents
+const maxDocumentSize = 32 << 20
+
+type document struct {
+	info       deepl.DocumentInfo
+	filename   string
+	targetLang string
+	content    []byte
+
+	polls            int
+	billedCharacters int
+	errorMessage     string
+}
+
+func (s *Server) handleDocumentUpload(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxDocumentSize); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	f, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Parameter 'file' not specified.")
+		return
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	filename := r.FormValue("filename")
+	if filename == "" {
+		filename = header.Filename
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".docx", ".pptx", ".xlsx", ".pdf", ".htm", ".html", ".txt", ".xlf", ".xliff", ".srt":
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid file data.")
+		return
+	}
+
+	targetLang := r.FormValue("target_lang")
+	if msg := validateLanguages(r.FormValue("source_lang"), targetLang, r.FormValue("formality")); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	characters := len(content)
+	if utf8.Valid(content) {
+		characters = utf8.RuneCount(content)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.consumeCharacters(characters) {
+		writeError(w, deepl.StatusQuotaExceeded, "Quota Exceeded")
+		return
+	}
+	s.documentCount++
+
+	n := s.nextID()
+	doc := &document{
+		info: deepl.DocumentInfo{
+			DocumentId:  fmt.Sprintf("%032X", n),
+			DocumentKey: fmt.Sprintf("%064X", n),
+		},
+		filename:         filename,
+		targetLang:       targetLang,
+		content:          content,
+		billedCharacters: characters,
+	}
+	if len(content) == 0 {
+		doc.errorMessage = "The document is empty."
+	}
+	s.documents[doc.info.DocumentId] = doc
+
+	writeJSON(w, http.StatusOK, doc.info)
+}
+
+func (s *Server) handleDocumentStatus(id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, ok := s.lookupDocument(w, r, id)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		status := deepl.DocumentStatus{
+			DocumentId: id,
+		}
+		switch {
+		case doc.polls == 0 && s.documentPolls > 0:
+			status.Status = deepl.DocumentStateQueued
+		case doc.polls < s.documentPolls:
+			status.Status = deepl.DocumentStateTranslating
+			status.SecondsRemaining = 1
+		case doc.errorMessage != "":
+			status.Status = deepl.DocumentStateError
+			status.ErrorMessage = doc.errorMessage
+		default:
+			status.Status = deepl.DocumentStateDone
+			status.BilledCharacters = doc.billedCharacters
+		}
+		doc.polls++
+
+		writeJSON(w, http.StatusOK, status)
+	}
+}
+
+func (s *Server) handleDocumentResult(id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, ok := s.lookupDocument(w, r, id)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		done := doc.polls > s.documentPolls && doc.errorMessage == ""
+		if done {
+			// documents can only be downloaded once
+			delete(s.documents, id)
+		}
+		s.mu.Unlock()
+
+		if !done {
+			writeError(w, http.StatusServiceUnavailable, "Document translation is not done yet")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.filename))
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, Translate(string(doc.content), doc.targetLang))
+	}
+}
+
+// lookupDocument returns the document with the given id if the request
+// provides its key, otherwise it writes an error response
+func (s *Server) lookupDocument(w http.ResponseWriter, r *http.Request, id string) (*document, bool) {
+	params, err := decodeRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.documents[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Document not found")
+		return nil, false
+	}
+	if param(params, "document_key") != doc.info.DocumentKey {
+		writeError(w, http.StatusForbidden, "Document key does not match")
+		return nil, false
+	}
+
+	return doc, true
+}
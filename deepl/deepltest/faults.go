@@ -0,0 +1,283 @@
+package deepltest
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+)
+
+// FaultKind is the kind of failure injected into a response.
+type FaultKind string
+
+const (
+	// Respond with status 429 and a `Retry-After` header
+	FaultTooManyRequests FaultKind = "429"
+	// Respond with status 456, i.e. the quota is exceeded
+	FaultQuotaExceeded FaultKind = "456"
+	// Respond with status 503 and an optional `Retry-After` header
+	FaultServiceUnavailable FaultKind = "503"
+	// Delay the response
+	FaultSlow FaultKind = "slow"
+	// Send only half of the response body
+	FaultTruncated FaultKind = "truncate"
+	// Respond with status 200 and an invalid JSON body
+	FaultMalformed FaultKind = "malformed"
+)
+
+// Fault describes a failure that is injected into matching requests.
+//
+// A fault is triggered on the Nth matching call and the Count-1 following
+// ones, or, if Nth is zero, randomly for the given percentage of calls.
+type Fault struct {
+	Kind FaultKind
+	// The endpoint path to match, e.g. `v2/translate`, which also matches
+	// sub-paths. If empty, all endpoints match.
+	Endpoint string
+
+	// The number of the first matching call to fail, starting at 1
+	Nth int
+	// The number of consecutive calls to fail, defaults to 1
+	Count int
+	// The percentage of matching calls to fail if Nth is zero
+	Percent float64
+
+	// The delay sent in the `Retry-After` header of 429 and 503 responses
+	RetryAfter time.Duration
+	// The delay of slow responses
+	Delay time.Duration
+}
+
+// WithFaults configures faults to inject into responses
+func WithFaults(faults ...Fault) Option {
+	return func(s *Server) {
+		for _, f := range faults {
+			s.faults = append(s.faults, &faultState{Fault: f})
+		}
+	}
+}
+
+// WithSeed sets the seed of the random number generator deciding whether
+// percentage based faults are triggered, the default seed is 1
+func WithSeed(seed int64) Option {
+	return func(s *Server) {
+		s.rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+// InjectFault adds a fault to inject into subsequent responses.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &faultState{Fault: f})
+}
+
+// ClearFaults removes all configured faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+type faultState struct {
+	Fault
+
+	calls int
+}
+
+func (f *faultState) matches(path string) bool {
+	endpoint := strings.Trim(f.Endpoint, "/")
+	if endpoint == "" || endpoint == "*" {
+		return true
+	}
+	return path == endpoint || strings.HasPrefix(path, endpoint+"/")
+}
+
+// trigger counts the call and reports whether the fault applies to it
+func (f *faultState) trigger(r *rand.Rand) bool {
+	f.calls++
+
+	if f.Nth > 0 {
+		count := f.Count
+		if count < 1 {
+			count = 1
+		}
+		return f.calls >= f.Nth && f.calls < f.Nth+count
+	}
+
+	return r.Float64()*100 < f.Percent
+}
+
+// triggeredFault returns the first fault that applies to a call of the given
+// path, if any
+func (s *Server) triggeredFault(path string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var triggered *Fault
+	for _, f := range s.faults {
+		if !f.matches(path) {
+			continue
+		}
+		// count the call for all matching faults
+		if f.trigger(s.rand) && triggered == nil {
+			fault := f.Fault
+			triggered = &fault
+		}
+	}
+
+	return triggered
+}
+
+// serveFault writes the response for the given fault, next serves the
+// request normally if the fault only alters the response
+func serveFault(w http.ResponseWriter, r *http.Request, f *Fault, next http.HandlerFunc) {
+	switch f.Kind {
+	case FaultTooManyRequests:
+		retryAfter := f.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = time.Second
+		}
+		setRetryAfter(w, retryAfter)
+		writeError(w, http.StatusTooManyRequests, "Too many requests")
+	case FaultQuotaExceeded:
+		writeError(w, deepl.StatusQuotaExceeded, "Quota Exceeded")
+	case FaultServiceUnavailable:
+		if f.RetryAfter > 0 {
+			setRetryAfter(w, f.RetryAfter)
+		}
+		writeError(w, http.StatusServiceUnavailable, "Service unavailable")
+	case FaultSlow:
+		timer := time.NewTimer(f.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			next(w, r)
+		case <-r.Context().Done():
+		}
+	case FaultTruncated:
+		rec := httptest.NewRecorder()
+		next(rec, r)
+
+		body := rec.Body.Bytes()
+		for k, vs := range rec.Header() {
+			w.Header()[k] = vs
+		}
+		// announce the full length so clients notice the truncation
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(body[:len(body)/2])
+	case FaultMalformed:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"malformed": `))
+	default:
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Unknown fault: %s", f.Kind))
+	}
+}
+
+func setRetryAfter(w http.ResponseWriter, d time.Duration) {
+	seconds := int((d + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
+
+// ParseFaults parses a comma-separated list of faults of the form
+//
+//	[ENDPOINT:]KIND[=DURATION][@TRIGGER]
+//
+// where KIND is one of `429`, `456`, `503`, `slow`, `truncate` or `malformed`,
+// DURATION is the `Retry-After` delay or the delay of slow responses, and
+// TRIGGER is either `N` to fail the Nth call, `NxM` to fail M calls starting
+// with the Nth, or `P%` to fail P percent of the calls. The endpoint may omit
+// the `v2/` prefix, without a trigger every call fails. For example:
+//
+//	translate:429=2s@1x3,usage:slow=500ms@50%,*:malformed@10
+func ParseFaults(spec string) ([]Fault, error) {
+	var faults []Fault
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		f, err := parseFault(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fault %q: %w", item, err)
+		}
+		faults = append(faults, f)
+	}
+	return faults, nil
+}
+
+func parseFault(s string) (Fault, error) {
+	var f Fault
+
+	if endpoint, rest, ok := strings.Cut(s, ":"); ok {
+		endpoint = strings.Trim(endpoint, "/")
+		if endpoint != "*" && endpoint != "" && !strings.HasPrefix(endpoint, "v2/") {
+			endpoint = "v2/" + endpoint
+		}
+		f.Endpoint = endpoint
+		s = rest
+	}
+
+	s, trigger, hasTrigger := strings.Cut(s, "@")
+	kind, param, hasParam := strings.Cut(s, "=")
+
+	f.Kind = FaultKind(kind)
+	switch f.Kind {
+	case FaultTooManyRequests, FaultServiceUnavailable, FaultSlow:
+		if !hasParam {
+			break
+		}
+		d, err := time.ParseDuration(param)
+		if err != nil {
+			return f, err
+		}
+		if f.Kind == FaultSlow {
+			f.Delay = d
+		} else {
+			f.RetryAfter = d
+		}
+	case FaultQuotaExceeded, FaultTruncated, FaultMalformed:
+		if hasParam {
+			return f, fmt.Errorf("fault %s does not take a value", kind)
+		}
+	default:
+		return f, fmt.Errorf("unknown fault kind: %s", kind)
+	}
+
+	switch {
+	case !hasTrigger:
+		f.Percent = 100
+	case strings.HasSuffix(trigger, "%"):
+		p, err := strconv.ParseFloat(strings.TrimSuffix(trigger, "%"), 64)
+		if err != nil || p < 0 || p > 100 {
+			return f, fmt.Errorf("invalid trigger: %s", trigger)
+		}
+		f.Percent = p
+	default:
+		nth, count, hasCount := strings.Cut(trigger, "x")
+		n, err := strconv.Atoi(nth)
+		if err != nil || n < 1 {
+			return f, fmt.Errorf("invalid trigger: %s", trigger)
+		}
+		f.Nth = n
+		if hasCount {
+			c, err := strconv.Atoi(count)
+			if err != nil || c < 1 {
+				return f, fmt.Errorf("invalid trigger: %s", trigger)
+			}
+			f.Count = c
+		}
+	}
+
+	return f, nil
+}
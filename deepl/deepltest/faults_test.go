@@ -0,0 +1,140 @@
+package deepltest
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFaults(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []Fault
+	}{
+		{
+			spec: "503",
+			want: []Fault{{Kind: FaultServiceUnavailable, Percent: 100}},
+		},
+		{
+			spec: "translate:429=2s@1x3",
+			want: []Fault{{Kind: FaultTooManyRequests, Endpoint: "v2/translate", RetryAfter: 2 * time.Second, Nth: 1, Count: 3}},
+		},
+		{
+			spec: "usage:slow=500ms@50%, *:malformed@10",
+			want: []Fault{
+				{Kind: FaultSlow, Endpoint: "v2/usage", Delay: 500 * time.Millisecond, Percent: 50},
+				{Kind: FaultMalformed, Endpoint: "*", Nth: 10},
+			},
+		},
+		{
+			spec: "/v2/document/:truncate@2,,456@0.5%",
+			want: []Fault{
+				{Kind: FaultTruncated, Endpoint: "v2/document", Nth: 2},
+				{Kind: FaultQuotaExceeded, Percent: 0.5},
+			},
+		},
+		{
+			spec: "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseFaults(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseFaults: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFaultsInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"teapot",
+		"translate:418",
+		"456=1s",
+		"malformed=1s",
+		"429=soon",
+		"503@0",
+		"503@first",
+		"503@1x0",
+		"503@1xmany",
+		"503@many%",
+		"503@150%",
+		"503@-1%",
+		"503,teapot",
+	} {
+		if faults, err := ParseFaults(spec); err == nil {
+			t.Errorf("ParseFaults(%q): got %+v, want error", spec, faults)
+		}
+	}
+}
+
+func TestInjectFault(t *testing.T) {
+	s := NewServer(WithDocumentPolls(0))
+	defer s.Close()
+
+	doc := uploadDocument(t, s, "hello.txt", "Hello")
+	key := url.Values{"document_key": {doc.DocumentKey}}
+
+	// the second and third call of the document endpoint and its sub-paths fail
+	s.InjectFault(Fault{Kind: FaultServiceUnavailable, Endpoint: "/v2/document/", Nth: 2, Count: 2})
+
+	calls := []struct {
+		path string
+		want int
+	}{
+		{"v2/document/" + doc.DocumentId, http.StatusOK},
+		{"v2/usage", http.StatusOK},
+		{"v2/document/" + doc.DocumentId, http.StatusServiceUnavailable},
+		{"v2/documents", http.StatusNotFound},
+		{"v2/document/" + doc.DocumentId + "/result", http.StatusServiceUnavailable},
+		{"v2/document/" + doc.DocumentId + "/result", http.StatusOK},
+	}
+	for i, c := range calls {
+		method := http.MethodPost
+		params := key
+		if c.path == "v2/usage" {
+			method, params = http.MethodGet, nil
+		}
+		if status, data := call(t, s, method, c.path, params); status != c.want {
+			t.Errorf("call %d to %s: got status %d, want %d: %s", i+1, c.path, status, c.want, data)
+		}
+	}
+
+	s.ClearFaults()
+	if status, _ := call(t, s, http.MethodGet, "v2/usage", nil); status != http.StatusOK {
+		t.Errorf("got status %d after clearing faults, want %d", status, http.StatusOK)
+	}
+}
+
+func TestInjectFaultPercent(t *testing.T) {
+	// the number of failed calls out of 200
+	failures := func(seed int64) int {
+		s := NewServer(WithSeed(seed), WithFaults(Fault{Kind: FaultQuotaExceeded, Endpoint: "v2/usage", Percent: 25}))
+		defer s.Close()
+
+		n := 0
+		for i := 0; i < 200; i++ {
+			if status, _ := call(t, s, http.MethodGet, "v2/usage", nil); status != http.StatusOK {
+				n++
+			}
+		}
+		return n
+	}
+
+	n := failures(1)
+	if n < 25 || n > 75 {
+		t.Errorf("got %d of 200 calls failing, want about 50", n)
+	}
+	// the same seed triggers the same faults
+	if m := failures(1); m != n {
+		t.Errorf("got %d failing calls with the same seed, want %d", m, n)
+	}
+}
@@ -0,0 +1,169 @@
+package deepltest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+)
+
+type glossary struct {
+	info    deepl.GlossaryInfo
+	entries []deepl.GlossaryEntry
+}
+
+func (s *Server) handleGlossaries(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		glossaries := make([]deepl.GlossaryInfo, 0, len(s.glossaries))
+		for _, g := range s.glossaries {
+			glossaries = append(glossaries, g.info)
+		}
+		sort.Slice(glossaries, func(i, j int) bool {
+			return glossaries[i].GlossaryId < glossaries[j].GlossaryId
+		})
+
+		writeJSON(w, http.StatusOK, map[string]any{"glossaries": glossaries})
+		return
+	}
+
+	params, err := decodeRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	name := param(params, "name")
+	sourceLang := strings.ToLower(param(params, "source_lang"))
+	targetLang := strings.ToLower(param(params, "target_lang"))
+	switch {
+	case name == "":
+		writeError(w, http.StatusBadRequest, "Parameter 'name' not specified.")
+		return
+	case !supportsGlossary(sourceLang, targetLang):
+		writeError(w, http.StatusBadRequest, "Unsupported glossary source and target language pair.")
+		return
+	}
+
+	var sep rune
+	switch param(params, "entries_format") {
+	case "tsv":
+		sep = '\t'
+	case "csv":
+		sep = ','
+	default:
+		writeError(w, http.StatusBadRequest, "Value for 'entries_format' not supported.")
+		return
+	}
+
+	entries, err := decodeGlossaryEntries(param(params, "entries"), sep)
+	if err != nil || len(entries) == 0 {
+		writeError(w, http.StatusBadRequest, "Invalid glossary entries provided.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID())
+	g := &glossary{
+		info: deepl.GlossaryInfo{
+			GlossaryId:   id,
+			Name:         name,
+			Ready:        true,
+			SourceLang:   sourceLang,
+			TargetLang:   targetLang,
+			CreationTime: time.Now().UTC().Format(time.RFC3339),
+			EntryCount:   len(entries),
+		},
+		entries: entries,
+	}
+	s.glossaries[id] = g
+
+	writeJSON(w, http.StatusCreated, g.info)
+}
+
+func (s *Server) handleGlossary(id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		g, ok := s.glossaries[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Glossary not found")
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			delete(s.glossaries, id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, g.info)
+	}
+}
+
+func (s *Server) handleGlossaryEntries(id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		g, ok := s.glossaries[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Glossary not found")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/tab-separated-values")
+		w.WriteHeader(http.StatusOK)
+		for _, entry := range g.entries {
+			fmt.Fprintf(w, "%s\t%s\n", entry.Source, entry.Target)
+		}
+	}
+}
+
+// lookupGlossaryLocked returns the glossary with the given id if it can be
+// used to translate between the given languages, otherwise an error message.
+// s.mu must be held.
+func (s *Server) lookupGlossaryLocked(id string, sourceLang string, targetLang string) (*glossary, string) {
+	if sourceLang == "" {
+		return nil, "Use of a glossary requires the 'source_lang' parameter to be specified."
+	}
+
+	g, ok := s.glossaries[id]
+	if !ok {
+		return nil, "Value for 'glossary_id' not supported."
+	}
+
+	if baseLanguage(g.info.SourceLang) != baseLanguage(sourceLang) || baseLanguage(g.info.TargetLang) != baseLanguage(targetLang) {
+		return nil, "Language pair of the glossary doesn't match the language pair of the request."
+	}
+
+	return g, ""
+}
+
+func decodeGlossaryEntries(data string, sep rune) ([]deepl.GlossaryEntry, error) {
+	r := csv.NewReader(strings.NewReader(data))
+	r.Comma = sep
+	r.FieldsPerRecord = 2
+	r.LazyQuotes = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]deepl.GlossaryEntry, 0, len(records))
+	for _, rec := range records {
+		entries = append(entries, deepl.GlossaryEntry{Source: rec[0], Target: rec[1]})
+	}
+
+	return entries, nil
+}
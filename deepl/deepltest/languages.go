@@ -0,0 +1,65 @@
+package deepltest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+)
+
+// The supported languages and glossary language pairs are those embedded in
+// the deepl package, so the server and the offline fallback agree.
+var (
+	sourceLanguages, _    = deepl.SnapshotLanguages("source")
+	targetLanguages, _    = deepl.SnapshotLanguages("target")
+	glossaryLanguagePairs = deepl.SnapshotGlossaryLanguagePairs()
+)
+
+func (s *Server) handleLanguages(w http.ResponseWriter, r *http.Request) {
+	params, err := decodeRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	switch param(params, "type") {
+	case "", "source":
+		writeJSON(w, http.StatusOK, sourceLanguages)
+	case "target":
+		writeJSON(w, http.StatusOK, targetLanguages)
+	default:
+		writeError(w, http.StatusBadRequest, "Value for 'type' not supported.")
+	}
+}
+
+func (s *Server) handleGlossaryLanguagePairs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"supported_languages": glossaryLanguagePairs})
+}
+
+// lookupLanguage returns the language with the given code, ignoring case
+func lookupLanguage(languages []deepl.Language, code string) (deepl.Language, bool) {
+	for _, lang := range languages {
+		if strings.EqualFold(lang.Code, code) {
+			return lang, true
+		}
+	}
+	return deepl.Language{}, false
+}
+
+// baseLanguage strips the regional variant from the given language code
+func baseLanguage(code string) string {
+	base, _, _ := strings.Cut(strings.ToUpper(code), "-")
+	return base
+}
+
+// supportsGlossary reports whether glossaries can be used to translate between
+// the languages, ignoring regional variants
+func supportsGlossary(sourceLang string, targetLang string) bool {
+	source, target := baseLanguage(sourceLang), baseLanguage(targetLang)
+	for _, pair := range glossaryLanguagePairs {
+		if strings.EqualFold(pair.SourceLang, source) && strings.EqualFold(pair.TargetLang, target) {
+			return true
+		}
+	}
+	return false
+}
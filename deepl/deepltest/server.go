@@ -0,0 +1,243 @@
+// Package deepltest provides a local stand-in for the DeepL API to be used in
+// tests.
+//
+// The server implements the endpoints used by the deepl package with
+// deterministic fake translations, authentication, quota accounting and
+// stateful glossaries and documents, e.g.
+//
+//	srv := deepltest.NewServer()
+//	defer srv.Close()
+//
+//	translator, err := deepl.NewTranslator(srv.AuthKey, deepl.WithServerURL(srv.URL))
+package deepltest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultAuthKey is the authentication key accepted by default
+	DefaultAuthKey = "deepltest-auth-key:fx"
+	// DefaultCharacterLimit is the character limit used by default
+	DefaultCharacterLimit = 500000
+)
+
+// Server is a fake DeepL API server.
+type Server struct {
+	*httptest.Server
+
+	// The authentication key accepted by the server
+	AuthKey string
+
+	characterLimit int
+	documentPolls  int
+
+	mu             sync.Mutex
+	characterCount int
+	documentCount  int
+	documents      map[string]*document
+	glossaries     map[string]*glossary
+	lastID         int
+	faults         []*faultState
+	rand           *rand.Rand
+}
+
+// Option is a functional option for configuring the Server
+type Option func(*Server)
+
+// WithAuthKey sets the authentication key accepted by the server
+func WithAuthKey(key string) Option {
+	return func(s *Server) {
+		s.AuthKey = key
+	}
+}
+
+// WithCharacterLimit sets the number of characters that can be translated
+// before requests fail with status 456, 0 means no limit
+func WithCharacterLimit(n int) Option {
+	return func(s *Server) {
+		s.characterLimit = n
+	}
+}
+
+// WithDocumentPolls sets how many status requests are answered with a pending
+// state before a document translation is done
+func WithDocumentPolls(n int) Option {
+	return func(s *Server) {
+		s.documentPolls = n
+	}
+}
+
+// NewServer starts and returns a new server. The caller should call Close
+// when finished, to shut it down.
+func NewServer(opts ...Option) *Server {
+	s := NewUnstartedServer(opts...)
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a new server that is not started yet, e.g. to
+// serve it on a specific listener.
+func NewUnstartedServer(opts ...Option) *Server {
+	s := &Server{
+		AuthKey:        DefaultAuthKey,
+		characterLimit: DefaultCharacterLimit,
+		documentPolls:  1,
+
+		documents:  make(map[string]*document),
+		glossaries: make(map[string]*glossary),
+		rand:       rand.New(rand.NewSource(1)),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.Server = httptest.NewUnstartedServer(s)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler, dispatching requests to the endpoints
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "DeepL-Auth-Key "+s.AuthKey {
+		writeError(w, http.StatusForbidden, "Authorization failure, check auth_key")
+		return
+	}
+
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[0] != "v2" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	if f := s.triggeredFault(path); f != nil {
+		serveFault(w, r, f, func(w http.ResponseWriter, r *http.Request) {
+			s.route(w, r, path, parts)
+		})
+		return
+	}
+
+	s.route(w, r, path, parts)
+}
+
+// route dispatches the request to the handler of the endpoint
+func (s *Server) route(w http.ResponseWriter, r *http.Request, path string, parts []string) {
+	switch {
+	case path == "v2/translate":
+		s.allow(w, r, s.handleTranslate, http.MethodPost)
+	case path == "v2/usage":
+		s.allow(w, r, s.handleUsage, http.MethodGet, http.MethodPost)
+	case path == "v2/languages":
+		s.allow(w, r, s.handleLanguages, http.MethodGet, http.MethodPost)
+	case path == "v2/glossary-language-pairs":
+		s.allow(w, r, s.handleGlossaryLanguagePairs, http.MethodGet)
+	case path == "v2/document":
+		s.allow(w, r, s.handleDocumentUpload, http.MethodPost)
+	case parts[1] == "document" && len(parts) == 3:
+		s.allow(w, r, s.handleDocumentStatus(parts[2]), http.MethodPost)
+	case parts[1] == "document" && len(parts) == 4 && parts[3] == "result":
+		s.allow(w, r, s.handleDocumentResult(parts[2]), http.MethodPost)
+	case path == "v2/glossaries":
+		s.allow(w, r, s.handleGlossaries, http.MethodGet, http.MethodPost)
+	case parts[1] == "glossaries" && len(parts) == 3:
+		s.allow(w, r, s.handleGlossary(parts[2]), http.MethodGet, http.MethodDelete)
+	case parts[1] == "glossaries" && len(parts) == 4 && parts[3] == "entries":
+		s.allow(w, r, s.handleGlossaryEntries(parts[2]), http.MethodGet)
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// allow calls the handler if the request method is one of the given methods
+func (s *Server) allow(w http.ResponseWriter, r *http.Request, h http.HandlerFunc, methods ...string) {
+	for _, m := range methods {
+		if r.Method == m {
+			h(w, r)
+			return
+		}
+	}
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+}
+
+// consumeCharacters accounts for the given number of characters, it reports
+// false if this would exceed the character limit. s.mu must be held.
+func (s *Server) consumeCharacters(n int) bool {
+	if s.characterLimit > 0 && s.characterCount+n > s.characterLimit {
+		return false
+	}
+	s.characterCount += n
+	return true
+}
+
+// nextID returns a new identifier, s.mu must be held
+func (s *Server) nextID() int {
+	s.lastID++
+	return s.lastID
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+// decodeRequest decodes the request parameters from either a JSON or a form
+// encoded body, JSON arrays are decoded into multiple values
+func decodeRequest(r *http.Request) (map[string][]string, error) {
+	params := make(map[string][]string)
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		for k, vs := range r.Form {
+			params[k] = vs
+		}
+		return params, nil
+	}
+
+	for k, vs := range r.URL.Query() {
+		params[k] = vs
+	}
+
+	var data map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	for k, v := range data {
+		switch v := v.(type) {
+		case []any:
+			for _, e := range v {
+				params[k] = append(params[k], fmt.Sprint(e))
+			}
+		case nil:
+		default:
+			params[k] = []string{fmt.Sprint(v)}
+		}
+	}
+
+	return params, nil
+}
+
+func param(params map[string][]string, name string) string {
+	if vs := params[name]; len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
@@ -0,0 +1,257 @@
+package deepltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+)
+
+// call sends a request with form encoded parameters to the server and returns
+// the status code and body of the response
+func call(t *testing.T, s *Server, method string, path string, params url.Values) (int, []byte) {
+	t.Helper()
+
+	var body io.Reader
+	if params != nil {
+		body = strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequest(method, s.URL+"/"+path, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+s.AuthKey)
+	if params != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	return do(t, s, req)
+}
+
+func do(t *testing.T, s *Server, req *http.Request) (int, []byte) {
+	t.Helper()
+
+	res, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.URL.Path, err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("%s %s: error reading response: %v", req.Method, req.URL.Path, err)
+	}
+
+	return res.StatusCode, data
+}
+
+func decode[T any](t *testing.T, data []byte) T {
+	t.Helper()
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("error decoding %q: %v", data, err)
+	}
+	return v
+}
+
+func uploadDocument(t *testing.T, s *Server, filename string, content string) deepl.DocumentInfo {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("target_lang", "DE")
+	fw, _ := mw.CreateFormFile("file", filename)
+	_, _ = io.WriteString(fw, content)
+	mw.Close()
+
+	req, err := http.NewRequest(http.MethodPost, s.URL+"/v2/document", &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+s.AuthKey)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	status, data := do(t, s, req)
+	if status != http.StatusOK {
+		t.Fatalf("upload: got status %d: %s", status, data)
+	}
+	return decode[deepl.DocumentInfo](t, data)
+}
+
+func TestServerAuthorization(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL+"/v2/usage", nil)
+	req.Header.Set("Authorization", "DeepL-Auth-Key invalid")
+
+	if status, _ := do(t, s, req); status != http.StatusForbidden {
+		t.Errorf("got status %d, want %d", status, http.StatusForbidden)
+	}
+}
+
+func TestServerDocumentStates(t *testing.T) {
+	s := NewServer(WithDocumentPolls(2))
+	defer s.Close()
+
+	doc := uploadDocument(t, s, "hello.txt", "Hello")
+	key := url.Values{"document_key": {doc.DocumentKey}}
+
+	// the result is not available before the translation is done
+	if status, _ := call(t, s, http.MethodPost, "v2/document/"+doc.DocumentId+"/result", key); status != http.StatusServiceUnavailable {
+		t.Errorf("early download: got status %d, want %d", status, http.StatusServiceUnavailable)
+	}
+
+	for _, want := range []deepl.DocumentState{deepl.DocumentStateQueued, deepl.DocumentStateTranslating, deepl.DocumentStateDone} {
+		status, data := call(t, s, http.MethodPost, "v2/document/"+doc.DocumentId, key)
+		if status != http.StatusOK {
+			t.Fatalf("status: got status %d: %s", status, data)
+		}
+		if got := decode[deepl.DocumentStatus](t, data); got.Status != want {
+			t.Errorf("got state %q, want %q", got.Status, want)
+		} else if want == deepl.DocumentStateDone && got.BilledCharacters != 5 {
+			t.Errorf("got %d billed characters, want 5", got.BilledCharacters)
+		}
+	}
+
+	// a wrong key is rejected
+	wrongKey := url.Values{"document_key": {"wrong"}}
+	if status, _ := call(t, s, http.MethodPost, "v2/document/"+doc.DocumentId+"/result", wrongKey); status != http.StatusForbidden {
+		t.Errorf("wrong key: got status %d, want %d", status, http.StatusForbidden)
+	}
+
+	status, data := call(t, s, http.MethodPost, "v2/document/"+doc.DocumentId+"/result", key)
+	if status != http.StatusOK {
+		t.Fatalf("download: got status %d: %s", status, data)
+	}
+	if want := Translate("Hello", "DE"); string(data) != want {
+		t.Errorf("got document %q, want %q", data, want)
+	}
+
+	// documents can only be downloaded once
+	if status, _ := call(t, s, http.MethodPost, "v2/document/"+doc.DocumentId+"/result", key); status != http.StatusNotFound {
+		t.Errorf("second download: got status %d, want %d", status, http.StatusNotFound)
+	}
+
+	if usage := s.Usage(); usage.DocumentCount != 1 || usage.CharacterCount != 5 {
+		t.Errorf("got usage %+v, want 1 document and 5 characters", usage)
+	}
+}
+
+func TestServerDocumentError(t *testing.T) {
+	s := NewServer(WithDocumentPolls(0))
+	defer s.Close()
+
+	doc := uploadDocument(t, s, "empty.txt", "")
+	key := url.Values{"document_key": {doc.DocumentKey}}
+
+	_, data := call(t, s, http.MethodPost, "v2/document/"+doc.DocumentId, key)
+	if got := decode[deepl.DocumentStatus](t, data); got.Status != deepl.DocumentStateError || got.ErrorMessage == "" {
+		t.Errorf("got status %+v, want error with message", got)
+	}
+
+	if status, _ := call(t, s, http.MethodPost, "v2/document/"+doc.DocumentId+"/result", key); status != http.StatusServiceUnavailable {
+		t.Errorf("download: got status %d, want %d", status, http.StatusServiceUnavailable)
+	}
+}
+
+func TestServerGlossaryStates(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	status, data := call(t, s, http.MethodPost, "v2/glossaries", url.Values{
+		"name":           {"greetings"},
+		"source_lang":    {"EN"},
+		"target_lang":    {"DE"},
+		"entries":        {"Hello\tHallo\nWorld\tWelt"},
+		"entries_format": {"tsv"},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("create: got status %d: %s", status, data)
+	}
+	info := decode[deepl.GlossaryInfo](t, data)
+	if info.SourceLang != "en" || info.TargetLang != "de" || info.EntryCount != 2 {
+		t.Errorf("got glossary %+v", info)
+	}
+
+	_, data = call(t, s, http.MethodGet, "v2/glossaries", nil)
+	list := decode[struct {
+		Glossaries []deepl.GlossaryInfo `json:"glossaries"`
+	}](t, data)
+	if len(list.Glossaries) != 1 || list.Glossaries[0].GlossaryId != info.GlossaryId {
+		t.Errorf("got glossaries %+v", list.Glossaries)
+	}
+
+	status, data = call(t, s, http.MethodGet, "v2/glossaries/"+info.GlossaryId+"/entries", nil)
+	if status != http.StatusOK || string(data) != "Hello\tHallo\nWorld\tWelt\n" {
+		t.Errorf("entries: got status %d: %q", status, data)
+	}
+
+	// the glossary requires a matching language pair
+	status, _ = call(t, s, http.MethodPost, "v2/translate", url.Values{
+		"text":        {"Hello"},
+		"source_lang": {"EN"},
+		"target_lang": {"FR"},
+		"glossary_id": {info.GlossaryId},
+	})
+	if status != http.StatusBadRequest {
+		t.Errorf("mismatching translate: got status %d, want %d", status, http.StatusBadRequest)
+	}
+
+	status, data = call(t, s, http.MethodPost, "v2/translate", url.Values{
+		"text":        {"Hello World"},
+		"source_lang": {"EN"},
+		"target_lang": {"DE"},
+		"glossary_id": {info.GlossaryId},
+	})
+	translations := decode[struct {
+		Translations []deepl.Translation `json:"translations"`
+	}](t, data).Translations
+	if status != http.StatusOK || len(translations) != 1 || translations[0].Text != Translate("Hallo Welt", "DE") {
+		t.Errorf("translate: got status %d: %s", status, data)
+	}
+
+	if status, _ := call(t, s, http.MethodDelete, "v2/glossaries/"+info.GlossaryId, nil); status != http.StatusNoContent {
+		t.Errorf("delete: got status %d, want %d", status, http.StatusNoContent)
+	}
+	for _, path := range []string{"v2/glossaries/" + info.GlossaryId, "v2/glossaries/" + info.GlossaryId + "/entries"} {
+		if status, _ := call(t, s, http.MethodGet, path, nil); status != http.StatusNotFound {
+			t.Errorf("%s after delete: got status %d, want %d", path, status, http.StatusNotFound)
+		}
+	}
+}
+
+func TestServerGlossaryValidation(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	tests := []struct {
+		name   string
+		params url.Values
+	}{
+		{"missing name", url.Values{"source_lang": {"EN"}, "target_lang": {"DE"}, "entries": {"a\tb"}, "entries_format": {"tsv"}}},
+		{"same languages", url.Values{"name": {"g"}, "source_lang": {"EN"}, "target_lang": {"EN"}, "entries": {"a\tb"}, "entries_format": {"tsv"}}},
+		{"unknown format", url.Values{"name": {"g"}, "source_lang": {"EN"}, "target_lang": {"DE"}, "entries": {"a\tb"}, "entries_format": {"xml"}}},
+		{"no entries", url.Values{"name": {"g"}, "source_lang": {"EN"}, "target_lang": {"DE"}, "entries": {""}, "entries_format": {"tsv"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, _ := call(t, s, http.MethodPost, "v2/glossaries", tt.params); status != http.StatusBadRequest {
+				t.Errorf("got status %d, want %d", status, http.StatusBadRequest)
+			}
+		})
+	}
+
+	_, data := call(t, s, http.MethodGet, "v2/glossaries", nil)
+	if !bytes.Contains(data, []byte(`"glossaries":[]`)) {
+		t.Errorf("got glossaries %s, want none", data)
+	}
+}
@@ -0,0 +1,141 @@
+package deepltest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+)
+
+// Translate returns the deterministic fake translation of the given text into
+// the target language, i.e. the text prefixed with the target language code.
+func Translate(text string, targetLang string) string {
+	return fmt.Sprintf("[%s] %s", strings.ToUpper(targetLang), text)
+}
+
+// DetectedSourceLanguage is the language reported for texts translated without
+// specifying the source language.
+const DetectedSourceLanguage = "EN"
+
+func (s *Server) handleTranslate(w http.ResponseWriter, r *http.Request) {
+	params, err := decodeRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	texts := params["text"]
+	if len(texts) == 0 {
+		writeError(w, http.StatusBadRequest, "Parameter 'text' not specified.")
+		return
+	}
+
+	sourceLang := param(params, "source_lang")
+	targetLang := param(params, "target_lang")
+	if msg := validateLanguages(sourceLang, targetLang, param(params, "formality")); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var modelTypeUsed string
+	switch modelType := param(params, "model_type"); modelType {
+	case "":
+	case "quality_optimized", "prefer_quality_optimized":
+		modelTypeUsed = "quality_optimized"
+	case "latency_optimized":
+		modelTypeUsed = "latency_optimized"
+	default:
+		writeError(w, http.StatusBadRequest, "Value for 'model_type' not supported.")
+		return
+	}
+	showBilled := param(params, "show_billed_characters") == "true" || param(params, "show_billed_characters") == "1"
+
+	var entries []deepl.GlossaryEntry
+	if id := param(params, "glossary_id"); id != "" {
+		g, msg := s.lookupGlossaryLocked(id, sourceLang, targetLang)
+		if msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+		entries = g.entries
+	}
+
+	var count int
+	for _, text := range texts {
+		count += utf8.RuneCountInString(text)
+	}
+	if !s.consumeCharacters(count) {
+		writeError(w, deepl.StatusQuotaExceeded, "Quota Exceeded")
+		return
+	}
+
+	detected := strings.ToUpper(sourceLang)
+	if detected == "" {
+		detected = DetectedSourceLanguage
+	}
+
+	translations := make([]deepl.Translation, 0, len(texts))
+	for _, text := range texts {
+		tr := deepl.Translation{
+			DetectedSourceLanguage: detected,
+			Text:                   Translate(applyGlossary(text, entries), targetLang),
+			ModelTypeUsed:          modelTypeUsed,
+		}
+		if showBilled {
+			tr.BilledCharacters = utf8.RuneCountInString(text)
+		}
+		translations = append(translations, tr)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"translations": translations})
+}
+
+// validateLanguages checks the language parameters of a translation request
+// and returns an error message if they are invalid
+func validateLanguages(sourceLang string, targetLang string, formality string) string {
+	if sourceLang != "" {
+		if _, ok := lookupLanguage(sourceLanguages, sourceLang); !ok {
+			return "Value for 'source_lang' not supported."
+		}
+	}
+
+	switch strings.ToUpper(targetLang) {
+	case "":
+		return "Parameter 'target_lang' not specified."
+	case "EN":
+		return "targetLang='en' is deprecated, please use 'en-GB' or 'en-US' instead."
+	case "PT":
+		return "targetLang='pt' is deprecated, please use 'pt-PT' or 'pt-BR' instead."
+	}
+
+	lang, ok := lookupLanguage(targetLanguages, targetLang)
+	if !ok {
+		return "Value for 'target_lang' not supported."
+	}
+
+	switch formality {
+	case "", "default", "prefer_more", "prefer_less":
+	case "more", "less":
+		if !lang.SupportsFormality {
+			return "'formality' is not supported for given 'target_lang'."
+		}
+	default:
+		return "Value for 'formality' not supported."
+	}
+
+	return ""
+}
+
+// applyGlossary replaces all occurences of the source terms with the target
+// terms of the given entries
+func applyGlossary(text string, entries []deepl.GlossaryEntry) string {
+	for _, entry := range entries {
+		text = strings.ReplaceAll(text, entry.Source, entry.Target)
+	}
+	return text
+}
@@ -0,0 +1,32 @@
+package deepltest
+
+import (
+	"net/http"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+)
+
+// Usage returns the current usage of the server's account.
+func (s *Server) Usage() deepl.Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return deepl.Usage{
+		CharacterCount: s.characterCount,
+		CharacterLimit: s.characterLimit,
+		DocumentCount:  s.documentCount,
+	}
+}
+
+// SetCharacterCount overrides the number of characters already translated,
+// e.g. to simulate an account close to its quota.
+func (s *Server) SetCharacterCount(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.characterCount = n
+}
+
+func (s *Server) handleUsage(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Usage())
+}
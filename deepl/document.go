@@ -2,13 +2,21 @@ package deepl
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/internal/retry"
 )
 
 type DocumentInfo struct {
@@ -16,100 +24,170 @@ type DocumentInfo struct {
 	DocumentKey string `json:"document_key"`
 }
 
+// DocumentState is the state of a document translation.
+type DocumentState string
+
+const (
+	// The translation job is waiting in line to be processed
+	DocumentStateQueued DocumentState = "queued"
+	// The translation is currently ongoing
+	DocumentStateTranslating DocumentState = "translating"
+	// The translation is done and the document can be downloaded
+	DocumentStateDone DocumentState = "done"
+	// An irrecoverable error occurred while translating the document
+	DocumentStateError DocumentState = "error"
+)
+
 type DocumentStatus struct {
-	DocumentId string `json:"document_id"`
-	Status     string `json:"status"`
+	DocumentId string        `json:"document_id"`
+	Status     DocumentState `json:"status"`
 
 	// Status dependent additional fields
-	SecondsRemaining string `json:"seconds_remaining:"`
+	SecondsRemaining int    `json:"seconds_remaining"`
 	BilledCharacters int    `json:"billed_characters"`
-	Message          string `json:"message"`
+	ErrorMessage     string `json:"error_message"`
+
+	// Deprecated: Use ErrorMessage instead.
+	Message string `json:"message"`
 }
 
-func (t *Translator) TranslateDocumentUpload(path string, targetLang string, opts ...TranslateOption) (*DocumentInfo, error) {
-	const (
-		endpoint string = "v2/document"
-		method   string = http.MethodPost
-	)
+// Done reports whether the translation has finished successfully.
+func (s *DocumentStatus) Done() bool {
+	return s.Status == DocumentStateDone
+}
 
-	// Gather translate options
-	options := TranslateOptions{}
-	if err := options.Gather(opts...); err != nil {
-		return nil, fmt.Errorf("error gathering options: %w", err)
+// Failed reports whether the translation has failed.
+func (s *DocumentStatus) Failed() bool {
+	return s.Status == DocumentStateError
+}
+
+// Pending reports whether the translation is queued or ongoing.
+func (s *DocumentStatus) Pending() bool {
+	return s.Status == DocumentStateQueued || s.Status == DocumentStateTranslating
+}
+
+// FailureMessage returns the error message of a failed translation.
+func (s *DocumentStatus) FailureMessage() string {
+	if s.ErrorMessage != "" {
+		return s.ErrorMessage
 	}
+	return s.Message
+}
+
+func (t *Translator) TranslateDocumentUpload(path string, targetLang string, opts ...DocumentOption) (*DocumentInfo, error) {
+	return t.TranslateDocumentUploadContext(context.Background(), path, targetLang, opts...)
+}
 
-	fields := map[string]*string{
-		"source_lang": options.SourceLang,
-		"formality":   options.Formality,
-		"glossary_id": options.GlossaryID,
+func (t *Translator) TranslateDocumentUploadContext(ctx context.Context, path string, targetLang string, opts ...DocumentOption) (*DocumentInfo, error) {
+	// Gather document options
+	options := DocumentOptions{}
+	if err := options.Gather(opts...); err != nil {
+		return nil, fmt.Errorf("error gathering options: %w", err)
 	}
 
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("error opening file: %w", err)
 	}
+	defer f.Close()
 
-	r, w := io.Pipe()
-	mpw := multipart.NewWriter(w)
-	errchan := make(chan error)
-	go func() {
-		defer w.Close()
-		defer f.Close()
-		defer close(errchan)
+	return t.uploadDocument(ctx, f, filepath.Base(path), "", targetLang, options)
+}
 
-		var part io.Writer
+// TranslateDocumentUploadReader uploads the document read from r for
+// translation into the specified target language.
+//
+// The filename is required to determine the document format, unless it is
+// overridden using WithFilename. If contentType is empty, it is derived from
+// the filename extension.
+//
+// The document is streamed from r if retries are disabled, e.g. using
+// WithoutRetries. Otherwise, unless r implements io.Seeker, the whole
+// document is buffered in memory to allow retrying the upload.
+func (t *Translator) TranslateDocumentUploadReader(ctx context.Context, r io.Reader, filename string, contentType string, targetLang string, opts ...DocumentOption) (*DocumentInfo, error) {
+	options := DocumentOptions{}
+	if err := options.Gather(opts...); err != nil {
+		return nil, fmt.Errorf("error gathering options: %w", err)
+	}
 
-		filename := filepath.Base(path)
+	if filename == "" && options.Filename == nil {
+		return nil, errors.New("document filename must not be empty")
+	}
 
-		if err := mpw.WriteField("filename", filename); err != nil {
-			errchan <- fmt.Errorf("error writing form field: %w", err)
-			return
-		}
-		if err := mpw.WriteField("target_lang", targetLang); err != nil {
-			errchan <- fmt.Errorf("error writing form field: %w", err)
-			return
-		}
+	return t.uploadDocument(ctx, r, filename, contentType, targetLang, options)
+}
 
-		for name, value := range fields {
-			if value == nil {
-				continue
-			}
+// uploadDocument uploads the document read from r for translation
+func (t *Translator) uploadDocument(ctx context.Context, r io.Reader, filename string, contentType string, targetLang string, options DocumentOptions) (*DocumentInfo, error) {
+	const (
+		endpoint string = "v2/document"
+		method   string = http.MethodPost
+	)
 
-			if err := mpw.WriteField(name, *value); err != nil {
-				errchan <- fmt.Errorf("error writing form field: %w", err)
-				return
-			}
-		}
+	if options.Filename != nil {
+		filename = *options.Filename
+	}
+	if err := validateDocumentFormat(filename, options.OutputFormat); err != nil {
+		return nil, err
+	}
+	if err := t.reserveBudget(ctx, 0); err != nil {
+		return nil, err
+	}
+
+	fields := [][2]string{
+		{"filename", filename},
+		{"target_lang", targetLang},
+	}
+	if options.SourceLang != nil {
+		fields = append(fields, [2]string{"source_lang", *options.SourceLang})
+	}
+	if options.Formality != nil {
+		fields = append(fields, [2]string{"formality", *options.Formality})
+	}
+	if options.GlossaryID != nil {
+		fields = append(fields, [2]string{"glossary_id", *options.GlossaryID})
+	}
+	if options.OutputFormat != nil {
+		fields = append(fields, [2]string{"output_format", *options.OutputFormat})
+	}
 
-		if part, err = mpw.CreateFormFile("file", filename); err != nil {
-			errchan <- fmt.Errorf("error creating form file: %w", err)
-			return
+	// the document is streamed, unless it must be read again for retries
+	getDocument := func() (io.Reader, error) { return r, nil }
+	if t.retryPolicy.MaxAttempts > 1 && !retriesDisabled(ctx) {
+		var err error
+		getDocument, err = replayableBody(r)
+		if err != nil {
+			return nil, fmt.Errorf("error reading document: %w", err)
 		}
-		if _, err = io.Copy(part, f); err != nil {
-			errchan <- fmt.Errorf("error writing form file: %w", err)
-			return
+	}
+	open := func() (io.ReadCloser, error) {
+		r, err := getDocument()
+		if err != nil {
+			return nil, err
 		}
+		return io.NopCloser(r), nil
+	}
 
-		if err = mpw.Close(); err != nil {
-			errchan <- fmt.Errorf("error closing multipart writer: %w", err)
-			return
-		}
-	}()
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	getBody, formContentType, stop := multipartBody(fields, filename, contentType, open)
+	defer stop()
 
 	headers := make(http.Header)
-	headers.Set("Content-Type", mpw.FormDataContentType())
+	headers.Set("Content-Type", formContentType)
 
-	res, err := t.callAPI(method, endpoint, headers, r)
-	merr := <-errchan
+	res, err := t.callAPIWithBody(ctx, method, endpoint, headers, getBody)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
-	if merr != nil {
-		return nil, merr
-	}
 	if res.StatusCode != http.StatusOK {
-		return nil, httpError(res.StatusCode)
+		return nil, httpError(endpoint, res)
 	}
 
 	var document DocumentInfo
@@ -121,6 +199,10 @@ func (t *Translator) TranslateDocumentUpload(path string, targetLang string, opt
 }
 
 func (t *Translator) TranslateDocumentStatus(id string, key string) (*DocumentStatus, error) {
+	return t.TranslateDocumentStatusContext(context.Background(), id, key)
+}
+
+func (t *Translator) TranslateDocumentStatusContext(ctx context.Context, id string, key string) (*DocumentStatus, error) {
 	var endpoint string = fmt.Sprintf("v2/document/%s", id)
 	const method string = http.MethodPost
 
@@ -138,24 +220,34 @@ func (t *Translator) TranslateDocumentStatus(id string, key string) (*DocumentSt
 		return nil, fmt.Errorf("error encoding request data: %w", err)
 	}
 
-	res, err := t.callAPI(method, endpoint, headers, bytes.NewReader(body))
+	res, err := t.callAPI(ctx, method, endpoint, headers, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return nil, httpError(res.StatusCode)
+		return nil, httpError(endpoint, res)
 	}
 
 	var status DocumentStatus
 	if err := json.NewDecoder(res.Body).Decode(&status); err != nil {
 		return nil, err
 	}
+	if status.Done() && (t.budget != nil || t.metrics != nil) && t.documents.markDone(id) {
+		t.budget.documentDone(status.BilledCharacters)
+		if t.metrics != nil {
+			t.metrics.CharactersBilled(endpointRoute(endpoint), status.BilledCharacters)
+		}
+	}
 
 	return &status, nil
 }
 
 func (t *Translator) TranslateDocumentDownload(id string, key string) (*io.PipeReader, error) {
+	return t.TranslateDocumentDownloadContext(context.Background(), id, key)
+}
+
+func (t *Translator) TranslateDocumentDownloadContext(ctx context.Context, id string, key string) (*io.PipeReader, error) {
 	var endpoint string = fmt.Sprintf("v2/document/%s/result", id)
 	const method string = http.MethodPost
 
@@ -173,14 +265,14 @@ func (t *Translator) TranslateDocumentDownload(id string, key string) (*io.PipeR
 		return nil, fmt.Errorf("error encoding request data: %w", err)
 	}
 
-	res, err := t.callAPI(method, endpoint, headers, bytes.NewReader(body))
+	res, err := t.callAPI(ctx, method, endpoint, headers, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 	if res.StatusCode != http.StatusOK {
-		defer res.Body.Close()
-		return nil, httpError(res.StatusCode)
+		return nil, httpError(endpoint, res)
 	}
+	t.documents.forget(id)
 
 	r, w := io.Pipe()
 	go func() {
@@ -194,3 +286,222 @@ func (t *Translator) TranslateDocumentDownload(id string, key string) (*io.PipeR
 
 	return r, nil
 }
+
+// DocumentError is returned if the translation of a document failed.
+type DocumentError struct {
+	DocumentId string
+	Message    string
+}
+
+func (e *DocumentError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("document %s: translation failed", e.DocumentId)
+	}
+	return fmt.Sprintf("document %s: translation failed: %s", e.DocumentId, e.Message)
+}
+
+// documentRetention is how long translated documents are remembered by a
+// documentTracker
+const documentRetention = time.Hour
+
+// documentTracker remembers the documents whose translation has been reported
+// as done, to count their billed characters only once. Documents are forgotten
+// once downloaded or after documentRetention.
+type documentTracker struct {
+	mu   sync.Mutex
+	done map[string]time.Time
+}
+
+// markDone reports whether the document was not marked as done before
+func (d *documentTracker) markDone(id string) bool {
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.done[id]; ok {
+		return false
+	}
+
+	for other, at := range d.done {
+		if now.Sub(at) > documentRetention {
+			delete(d.done, other)
+		}
+	}
+	if d.done == nil {
+		d.done = make(map[string]time.Time)
+	}
+	d.done[id] = now
+	return true
+}
+
+// forget removes the document, e.g. once it has been downloaded
+func (d *documentTracker) forget(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.done, id)
+}
+
+// documentPollBackoff is used to wait for document translations if the API
+// does not provide an estimate of the remaining time
+var documentPollBackoff = retry.Backoff{
+	InitialDelay: 1 * time.Second,
+	MaxDelay:     30 * time.Second,
+	Factor:       1.5,
+	Jitter:       0.1,
+}
+
+// TranslateDocument uploads the document read from in for translation into
+// the specified target language, waits for the translation to finish and
+// writes the translated document to out.
+//
+// It returns the number of characters billed for the translation.
+func (t *Translator) TranslateDocument(ctx context.Context, in io.Reader, filename string, out io.Writer, targetLang string, opts ...DocumentOption) (int, error) {
+	options := DocumentOptions{}
+	if err := options.Gather(opts...); err != nil {
+		return 0, fmt.Errorf("error gathering options: %w", err)
+	}
+
+	doc, err := t.uploadDocument(ctx, in, filename, "", targetLang, options)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.finishDocument(ctx, doc, out)
+}
+
+// finishDocument waits for the translation of the uploaded document to finish
+// and writes the translated document to out
+func (t *Translator) finishDocument(ctx context.Context, doc *DocumentInfo, out io.Writer) (int, error) {
+	status, err := t.waitForDocument(ctx, doc.DocumentId, doc.DocumentKey)
+	if err != nil {
+		return 0, err
+	}
+
+	r, err := t.TranslateDocumentDownloadContext(ctx, doc.DocumentId, doc.DocumentKey)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	if _, err := io.Copy(out, r); err != nil {
+		return 0, fmt.Errorf("error writing translated document: %w", err)
+	}
+
+	return status.BilledCharacters, nil
+}
+
+// waitForDocument polls the status of the given document until its
+// translation is done or has failed
+func (t *Translator) waitForDocument(ctx context.Context, id string, key string) (*DocumentStatus, error) {
+	for attempt := 0; ; attempt++ {
+		status, err := t.TranslateDocumentStatusContext(ctx, id, key)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case status.Done():
+			return status, nil
+		case status.Failed():
+			return nil, &DocumentError{DocumentId: id, Message: status.FailureMessage()}
+		}
+
+		delay := documentPollBackoff.Delay(attempt)
+		if status.SecondsRemaining > 0 {
+			delay = time.Duration(status.SecondsRemaining) * time.Second
+			if delay > documentPollBackoff.MaxDelay {
+				delay = documentPollBackoff.MaxDelay
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// multipartBody returns a function that streams a multipart form consisting
+// of the given fields and file on every call, along with the content type of
+// the form and a function that aborts and waits for the stream of the last
+// call, which must be called once the body is no longer needed.
+func multipartBody(fields [][2]string, filename string, fileContentType string, open func() (io.ReadCloser, error)) (bodyFunc, string, func()) {
+	// use the same boundary for every attempt to match the content type
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+
+	var (
+		last *io.PipeReader
+		done chan struct{}
+	)
+	stop := func() {
+		if last != nil {
+			_ = last.CloseWithError(errors.New("request body aborted")) // always returns nil
+			<-done
+			last, done = nil, nil
+		}
+	}
+
+	getBody := func() (io.Reader, error) {
+		// the previous attempt may still be reading from the file
+		stop()
+
+		f, err := open()
+		if err != nil {
+			return nil, err
+		}
+
+		r, w := io.Pipe()
+		last, done = r, make(chan struct{})
+		go func(done chan<- struct{}) {
+			defer close(done)
+			defer f.Close()
+
+			mpw := multipart.NewWriter(w)
+			if err := mpw.SetBoundary(boundary); err != nil {
+				_ = w.CloseWithError(err) // always returns nil
+				return
+			}
+
+			for _, field := range fields {
+				if err := mpw.WriteField(field[0], field[1]); err != nil {
+					_ = w.CloseWithError(fmt.Errorf("error writing form field: %w", err))
+					return
+				}
+			}
+
+			header := make(textproto.MIMEHeader)
+			header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+				"name":     "file",
+				"filename": filename,
+			}))
+			header.Set("Content-Type", fileContentType)
+
+			part, err := mpw.CreatePart(header)
+			if err != nil {
+				_ = w.CloseWithError(fmt.Errorf("error creating form file: %w", err))
+				return
+			}
+			if _, err := io.Copy(part, f); err != nil {
+				_ = w.CloseWithError(fmt.Errorf("error writing form file: %w", err))
+				return
+			}
+
+			if err := mpw.Close(); err != nil {
+				_ = w.CloseWithError(fmt.Errorf("error closing multipart writer: %w", err))
+				return
+			}
+			w.Close()
+		}(done)
+
+		return r, nil
+	}
+
+	contentType := "multipart/form-data; boundary=" + boundary
+
+	return getBody, contentType, stop
+}
@@ -0,0 +1,118 @@
+package deepl_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+func TestTranslateDocument(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithDocumentPolls(0))
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+
+	var out bytes.Buffer
+	billed, err := translator.TranslateDocument(context.Background(), strings.NewReader("Hello, World!"), "hello.txt", &out, "DE")
+	if err != nil {
+		t.Fatalf("TranslateDocument: %v", err)
+	}
+
+	if want := deepltest.Translate("Hello, World!", "DE"); out.String() != want {
+		t.Errorf("got document %q, want %q", out.String(), want)
+	}
+	if billed != 13 {
+		t.Errorf("got %d billed characters, want 13", billed)
+	}
+	if usage := srv.Usage(); usage.DocumentCount != 1 || usage.CharacterCount != 13 {
+		t.Errorf("got usage %+v, want 1 document and 13 characters", usage)
+	}
+}
+
+func TestTranslateDocumentSteps(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(path, []byte("Hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := translator.TranslateDocumentUploadContext(ctx, path, "FR")
+	if err != nil {
+		t.Fatalf("TranslateDocumentUploadContext: %v", err)
+	}
+
+	status, err := translator.TranslateDocumentStatusContext(ctx, doc.DocumentId, doc.DocumentKey)
+	if err != nil {
+		t.Fatalf("TranslateDocumentStatusContext: %v", err)
+	}
+	if !status.Pending() {
+		t.Errorf("got status %q, want pending", status.Status)
+	}
+
+	status, err = translator.TranslateDocumentStatusContext(ctx, doc.DocumentId, doc.DocumentKey)
+	if err != nil {
+		t.Fatalf("TranslateDocumentStatusContext: %v", err)
+	}
+	if !status.Done() {
+		t.Fatalf("got status %q, want done", status.Status)
+	}
+
+	r, err := translator.TranslateDocumentDownloadContext(ctx, doc.DocumentId, doc.DocumentKey)
+	if err != nil {
+		t.Fatalf("TranslateDocumentDownloadContext: %v", err)
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatalf("error reading document: %v", err)
+	}
+	if want := deepltest.Translate("Hello", "FR"); string(data) != want {
+		t.Errorf("got document %q, want %q", data, want)
+	}
+}
+
+func TestTranslateDocumentFailed(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+
+	_, err := translator.TranslateDocument(context.Background(), strings.NewReader(""), "empty.txt", io.Discard, "DE")
+
+	var docErr *deepl.DocumentError
+	if !errors.As(err, &docErr) {
+		t.Fatalf("got error %v, want *DocumentError", err)
+	}
+	if docErr.Message == "" {
+		t.Error("got empty error message")
+	}
+}
+
+func TestTranslateDocumentUnsupportedFormat(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	client := &recordingClient{client: srv.Client()}
+	translator := newTestTranslator(t, srv, deepl.WithHTTPClient(client))
+
+	_, err := translator.TranslateDocumentUploadReader(context.Background(), strings.NewReader("Hello"), "hello.exe", "", "DE")
+	if err == nil {
+		t.Fatal("got no error for unsupported document format")
+	}
+	if n := len(client.attempts("/v2/document")); n != 0 {
+		t.Errorf("got %d requests, want none", n)
+	}
+}
@@ -2,6 +2,7 @@ package deepl
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -25,6 +26,10 @@ type GlossaryInfo struct {
 }
 
 func (t *Translator) CreateGlossary(name string, sourceLang string, targetLang string, entries []GlossaryEntry) (*GlossaryInfo, error) {
+	return t.CreateGlossaryContext(context.Background(), name, sourceLang, targetLang, entries)
+}
+
+func (t *Translator) CreateGlossaryContext(ctx context.Context, name string, sourceLang string, targetLang string, entries []GlossaryEntry) (*GlossaryInfo, error) {
 	const (
 		endpoint string = "v2/glossaries"
 		method   string = http.MethodPost
@@ -52,13 +57,13 @@ func (t *Translator) CreateGlossary(name string, sourceLang string, targetLang s
 		return nil, fmt.Errorf("error encoding request data: %w", err)
 	}
 
-	res, err := t.callAPI(method, endpoint, headers, bytes.NewReader(body))
+	res, err := t.callAPI(ctx, method, endpoint, headers, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusCreated {
-		return nil, httpError(res.StatusCode)
+		return nil, httpError(endpoint, res)
 	}
 
 	var glossary GlossaryInfo
@@ -70,18 +75,22 @@ func (t *Translator) CreateGlossary(name string, sourceLang string, targetLang s
 }
 
 func (t *Translator) ListGlossaries() ([]GlossaryInfo, error) {
+	return t.ListGlossariesContext(context.Background())
+}
+
+func (t *Translator) ListGlossariesContext(ctx context.Context) ([]GlossaryInfo, error) {
 	const (
 		endpoint string = "v2/glossaries"
 		method   string = http.MethodGet
 	)
 
-	res, err := t.callAPI(method, endpoint, nil, nil)
+	res, err := t.callAPI(ctx, method, endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return nil, httpError(res.StatusCode)
+		return nil, httpError(endpoint, res)
 	}
 
 	var response struct {
@@ -95,16 +104,20 @@ func (t *Translator) ListGlossaries() ([]GlossaryInfo, error) {
 }
 
 func (t *Translator) GetGlossary(glossaryId string) (*GlossaryInfo, error) {
+	return t.GetGlossaryContext(context.Background(), glossaryId)
+}
+
+func (t *Translator) GetGlossaryContext(ctx context.Context, glossaryId string) (*GlossaryInfo, error) {
 	var endpoint string = fmt.Sprintf("v2/glossaries/%s", glossaryId)
 	const method string = http.MethodGet
 
-	res, err := t.callAPI(method, endpoint, nil, nil)
+	res, err := t.callAPI(ctx, method, endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return nil, httpError(res.StatusCode)
+		return nil, httpError(endpoint, res)
 	}
 
 	var glossary GlossaryInfo
@@ -116,35 +129,43 @@ func (t *Translator) GetGlossary(glossaryId string) (*GlossaryInfo, error) {
 }
 
 func (t *Translator) DeleteGlossary(glossaryId string) error {
+	return t.DeleteGlossaryContext(context.Background(), glossaryId)
+}
+
+func (t *Translator) DeleteGlossaryContext(ctx context.Context, glossaryId string) error {
 	var endpoint string = fmt.Sprintf("v2/glossaries/%s", glossaryId)
 	const method string = http.MethodDelete
 
-	res, err := t.callAPI(method, endpoint, nil, nil)
+	res, err := t.callAPI(ctx, method, endpoint, nil, nil)
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusNoContent {
-		return httpError(res.StatusCode)
+		return httpError(endpoint, res)
 	}
 
 	return nil
 }
 
 func (t *Translator) GetGlossaryEntries(glossaryId string) ([]GlossaryEntry, error) {
+	return t.GetGlossaryEntriesContext(context.Background(), glossaryId)
+}
+
+func (t *Translator) GetGlossaryEntriesContext(ctx context.Context, glossaryId string) ([]GlossaryEntry, error) {
 	var endpoint string = fmt.Sprintf("v2/glossaries/%s/entries", glossaryId)
 	const method string = http.MethodGet
 
 	headers := make(http.Header)
 	headers.Set("Accept", "text/tab-separated-values")
 
-	res, err := t.callAPI(method, endpoint, headers, nil)
+	res, err := t.callAPI(ctx, method, endpoint, headers, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return nil, httpError(res.StatusCode)
+		return nil, httpError(endpoint, res)
 	}
 
 	r := csv.NewReader(res.Body)
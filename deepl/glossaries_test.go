@@ -0,0 +1,85 @@
+package deepl_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+func TestGlossaries(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+
+	entries := []deepl.GlossaryEntry{
+		{Source: "Hello", Target: "Hallo"},
+		{Source: "World", Target: "Welt"},
+	}
+	info, err := translator.CreateGlossary("greetings", "EN", "DE", entries)
+	if err != nil {
+		t.Fatalf("CreateGlossary: %v", err)
+	}
+	if info.Name != "greetings" || info.EntryCount != 2 || !info.Ready {
+		t.Errorf("got glossary %+v", info)
+	}
+
+	glossaries, err := translator.ListGlossaries()
+	if err != nil {
+		t.Fatalf("ListGlossaries: %v", err)
+	}
+	if len(glossaries) != 1 || glossaries[0].GlossaryId != info.GlossaryId {
+		t.Errorf("got glossaries %+v, want %s", glossaries, info.GlossaryId)
+	}
+
+	got, err := translator.GetGlossary(info.GlossaryId)
+	if err != nil {
+		t.Fatalf("GetGlossary: %v", err)
+	}
+	if *got != *info {
+		t.Errorf("got glossary %+v, want %+v", got, info)
+	}
+
+	gotEntries, err := translator.GetGlossaryEntries(info.GlossaryId)
+	if err != nil {
+		t.Fatalf("GetGlossaryEntries: %v", err)
+	}
+	if !reflect.DeepEqual(gotEntries, entries) {
+		t.Errorf("got entries %+v, want %+v", gotEntries, entries)
+	}
+
+	translations, err := translator.TranslateText([]string{"Hello World"}, "DE",
+		deepl.WithSourceLang("EN"),
+		deepl.WithGlossaryID(info.GlossaryId),
+	)
+	if err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	if want := deepltest.Translate("Hallo Welt", "DE"); translations[0].Text != want {
+		t.Errorf("got translation %q, want %q", translations[0].Text, want)
+	}
+
+	if err := translator.DeleteGlossary(info.GlossaryId); err != nil {
+		t.Fatalf("DeleteGlossary: %v", err)
+	}
+	if _, err := translator.GetGlossary(info.GlossaryId); !errors.Is(err, deepl.ErrNotFound) {
+		t.Errorf("got error %v after deletion, want ErrNotFound", err)
+	}
+}
+
+func TestGlossaryUnsupportedLanguagePair(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+
+	_, err := translator.CreateGlossary("invalid", "EN", "EN", []deepl.GlossaryEntry{{Source: "a", Target: "b"}})
+
+	var apiErr *deepl.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got error %v, want *APIError", err)
+	}
+}
@@ -0,0 +1,61 @@
+package deepl
+
+import (
+	"context"
+	"io"
+)
+
+// TextTranslator translates texts.
+type TextTranslator interface {
+	TranslateTextContext(ctx context.Context, text []string, targetLang string, opts ...TranslateOption) ([]Translation, error)
+}
+
+// MultiTargetTranslator translates texts into multiple target languages at
+// once.
+type MultiTargetTranslator interface {
+	TextTranslator
+	TranslateToMany(ctx context.Context, texts []string, targets []string, opts ...TranslateOption) (map[string][]Translation, error)
+}
+
+// DocumentTranslator translates documents.
+type DocumentTranslator interface {
+	TranslateDocumentUploadContext(ctx context.Context, path string, targetLang string, opts ...DocumentOption) (*DocumentInfo, error)
+	TranslateDocumentUploadReader(ctx context.Context, r io.Reader, filename string, contentType string, targetLang string, opts ...DocumentOption) (*DocumentInfo, error)
+	TranslateDocumentStatusContext(ctx context.Context, id string, key string) (*DocumentStatus, error)
+	TranslateDocumentDownloadContext(ctx context.Context, id string, key string) (*io.PipeReader, error)
+	TranslateDocument(ctx context.Context, in io.Reader, filename string, out io.Writer, targetLang string, opts ...DocumentOption) (int, error)
+}
+
+// GlossaryManager creates, inspects and deletes glossaries.
+type GlossaryManager interface {
+	CreateGlossaryContext(ctx context.Context, name string, sourceLang string, targetLang string, entries []GlossaryEntry) (*GlossaryInfo, error)
+	ListGlossariesContext(ctx context.Context) ([]GlossaryInfo, error)
+	GetGlossaryContext(ctx context.Context, glossaryId string) (*GlossaryInfo, error)
+	GetGlossaryEntriesContext(ctx context.Context, glossaryId string) ([]GlossaryEntry, error)
+	DeleteGlossaryContext(ctx context.Context, glossaryId string) error
+	GetGlossaryLanguagePairsContext(ctx context.Context) ([]LanguagePair, error)
+}
+
+// LanguageReader retrieves the supported languages.
+type LanguageReader interface {
+	GetLanguagesContext(ctx context.Context, langType string) ([]Language, error)
+}
+
+// UsageReader retrieves usage information and account limits.
+type UsageReader interface {
+	GetUsageContext(ctx context.Context) (*Usage, error)
+}
+
+// Client combines all operations of the DeepL API.
+type Client interface {
+	TextTranslator
+	DocumentTranslator
+	GlossaryManager
+	LanguageReader
+	UsageReader
+}
+
+var (
+	_ Client                = (*Translator)(nil)
+	_ MultiTargetTranslator = (*Translator)(nil)
+)
@@ -0,0 +1,169 @@
+package deepl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// ErrUnsupportedLanguage is returned if a language is not supported by DeepL
+// in the requested role, i.e. as source or target language.
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// supported language codes as embedded at the time of release
+var (
+	sourceLangCodes = langCodeSet(snapshot.Source)
+	targetLangCodes = langCodeSet(snapshot.Target)
+)
+
+// targetLangVariants maps languages that must be translated into a regional
+// variant to their supported variants
+var targetLangVariants = map[string][]language.Tag{
+	"EN": {language.AmericanEnglish, language.BritishEnglish},
+	"PT": {language.BrazilianPortuguese, language.EuropeanPortuguese},
+}
+
+// ParseSourceLang parses a BCP 47 language tag, e.g. `fr-FR` or `fr_FR`, into
+// the DeepL source language code, e.g. `FR`.
+func ParseSourceLang(s string) (string, error) {
+	tag, err := parseLanguageTag(s)
+	if err != nil {
+		return "", err
+	}
+	return SourceLangFromTag(tag)
+}
+
+// ParseTargetLang parses a BCP 47 language tag, e.g. `en_AU` or `zh-TW`, into
+// the DeepL target language code, e.g. `EN-GB` or `ZH-HANT`.
+func ParseTargetLang(s string) (string, error) {
+	tag, err := parseLanguageTag(s)
+	if err != nil {
+		return "", err
+	}
+	return TargetLangFromTag(tag)
+}
+
+// SourceLangFromTag returns the DeepL source language code of the given tag.
+// Source languages have no variants, so the region and script are ignored.
+func SourceLangFromTag(tag language.Tag) (string, error) {
+	code, err := baseLangCode(tag)
+	if err != nil {
+		return "", err
+	}
+
+	if !sourceLangCodes[code] {
+		return "", fmt.Errorf("%w as source: %s", ErrUnsupportedLanguage, tag)
+	}
+	return code, nil
+}
+
+// TargetLangFromTag returns the DeepL target language code of the given tag.
+//
+// Regions are mapped to the closest supported variant, e.g. `en-AU` to
+// `EN-GB`. English and Portuguese are only supported as target languages with
+// a region.
+func TargetLangFromTag(tag language.Tag) (string, error) {
+	code, err := baseLangCode(tag)
+	if err != nil {
+		return "", err
+	}
+
+	_, script, region := tag.Raw()
+	switch {
+	case targetLangVariants[code] != nil:
+		if region.String() == "ZZ" {
+			return "", fmt.Errorf("%w as target: %s, use one of %s", ErrUnsupportedLanguage, tag, strings.Join(variantCodes(code), ", "))
+		}
+		_, index, _ := language.NewMatcher(targetLangVariants[code]).Match(tag)
+		code = langCode(targetLangVariants[code][index])
+	case code == "ZH":
+		if script.String() == "Zzzz" && region.String() == "ZZ" {
+			break
+		}
+		// infer the script from the region, e.g. `zh-TW` is written in
+		// traditional characters
+		script, _ = tag.Script()
+		switch script.String() {
+		case "Hans":
+			code = "ZH-HANS"
+		case "Hant":
+			code = "ZH-HANT"
+		}
+	}
+
+	if !targetLangCodes[code] {
+		return "", fmt.Errorf("%w as target: %s", ErrUnsupportedLanguage, tag)
+	}
+	return code, nil
+}
+
+// ValidateLanguagePair checks that the given DeepL language codes can be used
+// as source and target language of a translation. An empty source language
+// requests automatic detection and is always valid.
+func ValidateLanguagePair(sourceLang string, targetLang string) error {
+	if sourceLang != "" && !sourceLangCodes[strings.ToUpper(sourceLang)] {
+		return fmt.Errorf("%w as source: %s", ErrUnsupportedLanguage, sourceLang)
+	}
+
+	code := strings.ToUpper(targetLang)
+	if variants := targetLangVariants[code]; variants != nil {
+		return fmt.Errorf("%w as target: %s, use one of %s", ErrUnsupportedLanguage, targetLang, strings.Join(variantCodes(code), ", "))
+	}
+	if !targetLangCodes[code] {
+		return fmt.Errorf("%w as target: %s", ErrUnsupportedLanguage, targetLang)
+	}
+
+	return nil
+}
+
+// Tag returns the BCP 47 language tag of the language, e.g. `en-GB` for
+// `EN-GB`.
+func (l Language) Tag() (language.Tag, error) {
+	return language.Parse(l.Code)
+}
+
+func parseLanguageTag(s string) (language.Tag, error) {
+	tag, err := language.Parse(strings.ReplaceAll(strings.TrimSpace(s), "_", "-"))
+	if err != nil {
+		return language.Und, fmt.Errorf("%w: %q", ErrUnsupportedLanguage, s)
+	}
+	return tag, nil
+}
+
+// baseLangCode returns the upper case language subtag of the given tag
+func baseLangCode(tag language.Tag) (string, error) {
+	base, confidence := tag.Base()
+	if confidence == language.No {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedLanguage, tag)
+	}
+
+	code := strings.ToUpper(base.String())
+	if code == "NO" {
+		// DeepL uses the code of Norwegian Bokmål
+		code = "NB"
+	}
+	return code, nil
+}
+
+// langCode formats a language tag with region as DeepL code, e.g. `EN-GB`
+func langCode(tag language.Tag) string {
+	return strings.ToUpper(tag.String())
+}
+
+func langCodeSet(languages []Language) map[string]bool {
+	codes := make(map[string]bool, len(languages))
+	for _, lang := range languages {
+		codes[strings.ToUpper(lang.Code)] = true
+	}
+	return codes
+}
+
+func variantCodes(code string) []string {
+	var codes []string
+	for _, tag := range targetLangVariants[code] {
+		codes = append(codes, langCode(tag))
+	}
+	return codes
+}
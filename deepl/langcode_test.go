@@ -0,0 +1,115 @@
+package deepl_test
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+)
+
+func TestParseLang(t *testing.T) {
+	tests := []struct {
+		input  string
+		source string
+		// an empty target means that the language is unsupported as target
+		target string
+	}{
+		{input: "de", source: "DE", target: "DE"},
+		{input: "fr-FR", source: "FR", target: "FR"},
+		{input: "fr_CA", source: "FR", target: "FR"},
+		{input: " ja ", source: "JA", target: "JA"},
+		{input: "en-US", source: "EN", target: "EN-US"},
+		{input: "en_GB", source: "EN", target: "EN-GB"},
+		{input: "en_AU", source: "EN", target: "EN-GB"},
+		{input: "EN", source: "EN"},
+		{input: "pt-BR", source: "PT", target: "PT-BR"},
+		{input: "pt-PT", source: "PT", target: "PT-PT"},
+		{input: "pt-AO", source: "PT", target: "PT-PT"},
+		{input: "PT", source: "PT"},
+		{input: "zh", source: "ZH", target: "ZH"},
+		{input: "zh-CN", source: "ZH", target: "ZH-HANS"},
+		{input: "zh-TW", source: "ZH", target: "ZH-HANT"},
+		{input: "zh-Hant", source: "ZH", target: "ZH-HANT"},
+		{input: "no", source: "NB", target: "NB"},
+		{input: "nb-NO", source: "NB", target: "NB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			source, err := deepl.ParseSourceLang(tt.input)
+			if err != nil || source != tt.source {
+				t.Errorf("ParseSourceLang: got %q, %v, want %q", source, err, tt.source)
+			}
+
+			target, err := deepl.ParseTargetLang(tt.input)
+			if tt.target == "" {
+				if !errors.Is(err, deepl.ErrUnsupportedLanguage) {
+					t.Errorf("ParseTargetLang: got %q, %v, want ErrUnsupportedLanguage", target, err)
+				}
+			} else if err != nil || target != tt.target {
+				t.Errorf("ParseTargetLang: got %q, %v, want %q", target, err, tt.target)
+			}
+		})
+	}
+}
+
+func TestParseLangUnsupported(t *testing.T) {
+	for _, input := range []string{"", "xx", "not a tag", "tlh"} {
+		if code, err := deepl.ParseSourceLang(input); !errors.Is(err, deepl.ErrUnsupportedLanguage) {
+			t.Errorf("ParseSourceLang(%q): got %q, %v, want ErrUnsupportedLanguage", input, code, err)
+		}
+		if code, err := deepl.ParseTargetLang(input); !errors.Is(err, deepl.ErrUnsupportedLanguage) {
+			t.Errorf("ParseTargetLang(%q): got %q, %v, want ErrUnsupportedLanguage", input, code, err)
+		}
+	}
+}
+
+func TestTargetLangFromTag(t *testing.T) {
+	tests := []struct {
+		tag  language.Tag
+		want string
+	}{
+		{language.BritishEnglish, "EN-GB"},
+		{language.AmericanEnglish, "EN-US"},
+		{language.BrazilianPortuguese, "PT-BR"},
+		{language.SimplifiedChinese, "ZH-HANS"},
+		{language.TraditionalChinese, "ZH-HANT"},
+		{language.German, "DE"},
+	}
+	for _, tt := range tests {
+		if got, err := deepl.TargetLangFromTag(tt.tag); err != nil || got != tt.want {
+			t.Errorf("TargetLangFromTag(%s): got %q, %v, want %q", tt.tag, got, err, tt.want)
+		}
+	}
+
+	if _, err := deepl.TargetLangFromTag(language.English); !errors.Is(err, deepl.ErrUnsupportedLanguage) {
+		t.Errorf("TargetLangFromTag(en): got error %v, want ErrUnsupportedLanguage", err)
+	}
+}
+
+func TestValidateLanguagePair(t *testing.T) {
+	tests := []struct {
+		source, target string
+		valid          bool
+	}{
+		{"", "DE", true},
+		{"EN", "de", true},
+		{"EN", "EN-GB", true},
+		{"en", "ZH-HANT", true},
+		{"EN", "EN", false},
+		{"DE", "PT", false},
+		{"XX", "DE", false},
+		{"EN", "XX", false},
+		{"EN-GB", "DE", false},
+	}
+	for _, tt := range tests {
+		err := deepl.ValidateLanguagePair(tt.source, tt.target)
+		if tt.valid && err != nil {
+			t.Errorf("ValidateLanguagePair(%q, %q): got error %v", tt.source, tt.target, err)
+		} else if !tt.valid && !errors.Is(err, deepl.ErrUnsupportedLanguage) {
+			t.Errorf("ValidateLanguagePair(%q, %q): got error %v, want ErrUnsupportedLanguage", tt.source, tt.target, err)
+		}
+	}
+}
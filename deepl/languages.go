@@ -2,6 +2,7 @@ package deepl
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -18,7 +19,43 @@ type LanguagePair struct {
 	TargetLang string `json:"target_lang"`
 }
 
+// GetLanguages retrieves the languages of the given type, `source` or
+// `target`, supported by the API.
+//
+// The languages are cached for the duration set by WithMetadataTTL.
 func (t *Translator) GetLanguages(langType string) ([]Language, error) {
+	return t.GetLanguagesContext(context.Background(), langType)
+}
+
+// GetLanguagesContext is like GetLanguages but uses the given context for the
+// underlying requests.
+func (t *Translator) GetLanguagesContext(ctx context.Context, langType string) ([]Language, error) {
+	switch langType {
+	case "", "source":
+		// if omitted, default is `source`
+		langType = "source"
+	case "target":
+	default:
+		return nil, fmt.Errorf("Invalid languages `type` value: %v", langType)
+	}
+
+	if cached, ok := t.metadata.getLanguages(langType); ok && cached.fresh(t.metadata.ttl) {
+		return append([]Language(nil), cached.value...), nil
+	}
+
+	languages, err := t.getLanguages(ctx, langType)
+	if err != nil {
+		return nil, err
+	}
+	if t.metadata.ttl > 0 {
+		t.metadata.setLanguages(langType, languages)
+	}
+
+	return append([]Language(nil), languages...), nil
+}
+
+// getLanguages requests the languages of the given type
+func (t *Translator) getLanguages(ctx context.Context, langType string) ([]Language, error) {
 	const (
 		endpoint string = "v2/languages"
 		method   string = http.MethodGet
@@ -26,15 +63,8 @@ func (t *Translator) GetLanguages(langType string) ([]Language, error) {
 
 	opts := struct {
 		Type string `json:"type,omitempty"`
-	}{}
-
-	switch langType {
-	case "":
-		// if omitted, default is `source`
-	case "source", "target":
-		opts.Type = langType
-	default:
-		return nil, fmt.Errorf("Invalid languages `type` value: %v", langType)
+	}{
+		Type: langType,
 	}
 
 	headers := make(http.Header)
@@ -45,13 +75,13 @@ func (t *Translator) GetLanguages(langType string) ([]Language, error) {
 		return nil, fmt.Errorf("error encoding request data: %w", err)
 	}
 
-	res, err := t.callAPI(method, endpoint, headers, bytes.NewReader(body))
+	res, err := t.callAPI(ctx, method, endpoint, headers, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return nil, httpError(res.StatusCode)
+		return nil, httpError(endpoint, res)
 	}
 
 	var languages []Language
@@ -62,19 +92,46 @@ func (t *Translator) GetLanguages(langType string) ([]Language, error) {
 	return languages, nil
 }
 
+// GetGlossaryLanguagePairs retrieves the language pairs supported for
+// glossaries.
+//
+// The language pairs are cached for the duration set by WithMetadataTTL.
 func (t *Translator) GetGlossaryLanguagePairs() ([]LanguagePair, error) {
+	return t.GetGlossaryLanguagePairsContext(context.Background())
+}
+
+// GetGlossaryLanguagePairsContext is like GetGlossaryLanguagePairs but uses the
+// given context for the underlying requests.
+func (t *Translator) GetGlossaryLanguagePairsContext(ctx context.Context) ([]LanguagePair, error) {
+	if cached := t.metadata.getPairs(); cached.fresh(t.metadata.ttl) {
+		return append([]LanguagePair(nil), cached.value...), nil
+	}
+
+	pairs, err := t.getGlossaryLanguagePairs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t.metadata.ttl > 0 {
+		t.metadata.setPairs(pairs)
+	}
+
+	return append([]LanguagePair(nil), pairs...), nil
+}
+
+// getGlossaryLanguagePairs requests the glossary language pairs
+func (t *Translator) getGlossaryLanguagePairs(ctx context.Context) ([]LanguagePair, error) {
 	const (
 		endpoint string = "v2/glossary-language-pairs"
 		method   string = http.MethodGet
 	)
 
-	res, err := t.callAPI(method, endpoint, nil, nil)
+	res, err := t.callAPI(ctx, method, endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return nil, httpError(res.StatusCode)
+		return nil, httpError(endpoint, res)
 	}
 
 	var response struct {
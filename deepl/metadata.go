@@ -0,0 +1,283 @@
+package deepl
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMetadataTTL = 24 * time.Hour
+
+	// metadataLookupTimeout bounds fetching metadata for lookups that can
+	// fall back to cached or embedded metadata
+	metadataLookupTimeout = 5 * time.Second
+	// metadataFailureTTL is for how long such lookups do not fetch metadata
+	// again after fetching it failed
+	metadataFailureTTL = time.Minute
+)
+
+// languagesSnapshot holds the supported languages and glossary language pairs
+// at the time of release, they are used if the API cannot be reached.
+//
+//go:embed languages.json
+var languagesSnapshot []byte
+
+type metadataSnapshot struct {
+	Source                []Language     `json:"source"`
+	Target                []Language     `json:"target"`
+	GlossaryLanguagePairs []LanguagePair `json:"glossary_language_pairs"`
+}
+
+var snapshot = loadSnapshot()
+
+func loadSnapshot() metadataSnapshot {
+	var s metadataSnapshot
+	if err := json.Unmarshal(languagesSnapshot, &s); err != nil {
+		panic("invalid languages snapshot: " + err.Error())
+	}
+	return s
+}
+
+// SnapshotLanguages returns the languages of the given type, `source` or
+// `target`, as embedded at the time of release.
+func SnapshotLanguages(langType string) ([]Language, error) {
+	switch langType {
+	case "", "source":
+		return append([]Language(nil), snapshot.Source...), nil
+	case "target":
+		return append([]Language(nil), snapshot.Target...), nil
+	default:
+		return nil, fmt.Errorf("Invalid languages `type` value: %v", langType)
+	}
+}
+
+// SnapshotGlossaryLanguagePairs returns the glossary language pairs as embedded
+// at the time of release.
+func SnapshotGlossaryLanguagePairs() []LanguagePair {
+	return append([]LanguagePair(nil), snapshot.GlossaryLanguagePairs...)
+}
+
+// WithMetadataTTL sets for how long supported languages and glossary language
+// pairs are cached, 0 disables caching
+func WithMetadataTTL(d time.Duration) TranslatorOption {
+	return func(t *Translator) error {
+		if d < 0 {
+			return errors.New("metadata ttl must be non-negative")
+		}
+		t.metadata.ttl = d
+		return nil
+	}
+}
+
+// metadataCache caches the supported languages and glossary language pairs
+type metadataCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	languages map[string]cachedValue[[]Language]
+	pairs     cachedValue[[]LanguagePair]
+	glossary  map[string]LanguagePair
+	failed    map[string]time.Time
+}
+
+type cachedValue[T any] struct {
+	value   T
+	fetched time.Time
+}
+
+func (v cachedValue[T]) fresh(ttl time.Duration) bool {
+	return !v.fetched.IsZero() && time.Since(v.fetched) < ttl
+}
+
+func (c *metadataCache) getLanguages(langType string) (cachedValue[[]Language], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.languages[langType]
+	return v, ok
+}
+
+func (c *metadataCache) setLanguages(langType string, languages []Language) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.languages == nil {
+		c.languages = make(map[string]cachedValue[[]Language])
+	}
+	c.languages[langType] = cachedValue[[]Language]{value: languages, fetched: time.Now()}
+	delete(c.failed, languagesLookupKey(langType))
+}
+
+func (c *metadataCache) getPairs() cachedValue[[]LanguagePair] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pairs
+}
+
+func (c *metadataCache) setPairs(pairs []LanguagePair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pairs = cachedValue[[]LanguagePair]{value: pairs, fetched: time.Now()}
+	delete(c.failed, pairsLookupKey)
+}
+
+func (c *metadataCache) getGlossaryPair(glossaryID string) (LanguagePair, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pair, ok := c.glossary[glossaryID]
+	return pair, ok
+}
+
+func (c *metadataCache) setGlossaryPair(glossaryID string, pair LanguagePair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.glossary == nil {
+		c.glossary = make(map[string]LanguagePair)
+	}
+	c.glossary[glossaryID] = pair
+}
+
+// failedRecently reports whether fetching the metadata with the given key
+// failed within the failure ttl
+func (c *metadataCache) failedRecently(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	failed, ok := c.failed[key]
+	return ok && time.Since(failed) < metadataFailureTTL
+}
+
+func (c *metadataCache) setFailed(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failed == nil {
+		c.failed = make(map[string]time.Time)
+	}
+	c.failed[key] = time.Now()
+}
+
+// keys of the metadata fetched by lookups
+const pairsLookupKey = "glossary-language-pairs"
+
+func languagesLookupKey(langType string) string {
+	return "languages/" + langType
+}
+
+// errMetadataUnavailable is returned by lookupMetadata if fetching the
+// metadata failed recently
+var errMetadataUnavailable = errors.New("metadata unavailable")
+
+// lookupMetadata calls fetch to retrieve the metadata with the given key for a
+// lookup that can fall back to cached or embedded metadata. To not hold up
+// the lookup, the metadata is fetched without retries and a short timeout,
+// and not at all for a while after this failed.
+func (t *Translator) lookupMetadata(ctx context.Context, key string, fetch func(ctx context.Context) error) error {
+	if t.metadata.failedRecently(key) {
+		return errMetadataUnavailable
+	}
+
+	fetchCtx, cancel := context.WithTimeout(withoutRetries(ctx), metadataLookupTimeout)
+	defer cancel()
+
+	err := fetch(fetchCtx)
+	if err != nil && ctx.Err() == nil {
+		t.metadata.setFailed(key)
+	}
+	return err
+}
+
+// SupportsFormality reports whether the formality option can be used with the
+// given target language.
+//
+// The supported languages are fetched from the API and cached, if they cannot
+// be retrieved previously cached or embedded ones are used. Failures are not
+// retried and remembered for a minute, during which the fallback is used
+// right away.
+func (t *Translator) SupportsFormality(targetLang string) bool {
+	return t.SupportsFormalityContext(context.Background(), targetLang)
+}
+
+// SupportsFormalityContext is like SupportsFormality but uses the given context
+// for the underlying requests.
+func (t *Translator) SupportsFormalityContext(ctx context.Context, targetLang string) bool {
+	for _, lang := range t.languagesWithFallback(ctx, "target") {
+		if strings.EqualFold(lang.Code, targetLang) {
+			return lang.SupportsFormality
+		}
+	}
+	return false
+}
+
+// GlossaryPairSupported reports whether glossaries can be created for and used
+// with the given source and target language. Regional variants are ignored,
+// e.g. `EN-GB` is treated as `EN`.
+//
+// The supported pairs are fetched from the API and cached, if they cannot be
+// retrieved previously cached or embedded ones are used. Failures are handled
+// like for SupportsFormality.
+func (t *Translator) GlossaryPairSupported(sourceLang string, targetLang string) bool {
+	return t.GlossaryPairSupportedContext(context.Background(), sourceLang, targetLang)
+}
+
+// GlossaryPairSupportedContext is like GlossaryPairSupported but uses the
+// given context for the underlying requests.
+func (t *Translator) GlossaryPairSupportedContext(ctx context.Context, sourceLang string, targetLang string) bool {
+	var pairs []LanguagePair
+	err := t.lookupMetadata(ctx, pairsLookupKey, func(ctx context.Context) error {
+		var err error
+		pairs, err = t.GetGlossaryLanguagePairsContext(ctx)
+		return err
+	})
+	if err != nil {
+		if cached := t.metadata.getPairs(); !cached.fetched.IsZero() {
+			pairs = cached.value
+		} else {
+			pairs = snapshot.GlossaryLanguagePairs
+		}
+	}
+
+	source, target := baseLangCodeOf(sourceLang), baseLangCodeOf(targetLang)
+	for _, pair := range pairs {
+		if strings.EqualFold(pair.SourceLang, source) && strings.EqualFold(pair.TargetLang, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// languagesWithFallback returns the languages of the given type, using cached
+// or embedded ones if they cannot be retrieved
+func (t *Translator) languagesWithFallback(ctx context.Context, langType string) []Language {
+	var languages []Language
+	err := t.lookupMetadata(ctx, languagesLookupKey(langType), func(ctx context.Context) error {
+		var err error
+		languages, err = t.GetLanguagesContext(ctx, langType)
+		return err
+	})
+	if err == nil {
+		return languages
+	}
+
+	if cached, ok := t.metadata.getLanguages(langType); ok {
+		return cached.value
+	}
+	languages, _ = SnapshotLanguages(langType)
+	return languages
+}
+
+// baseLangCodeOf strips the regional variant from the given language code
+func baseLangCodeOf(code string) string {
+	base, _, _ := strings.Cut(code, "-")
+	return base
+}
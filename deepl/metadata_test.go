@@ -0,0 +1,74 @@
+package deepl_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+func TestMetadataFallbackDuringOutage(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:     deepltest.FaultServiceUnavailable,
+		Endpoint: "v2/languages",
+		Percent:  100,
+	}))
+	defer srv.Close()
+
+	client := &recordingClient{client: srv.Client()}
+	translator := newTestTranslator(t, srv,
+		deepl.WithHTTPClient(client),
+		deepl.WithStrictValidation(),
+		deepl.WithRetryPolicy(deepl.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff: deepl.Backoff{
+				InitialDelay: 100 * time.Millisecond,
+				MaxDelay:     time.Second,
+				Factor:       1,
+			},
+		}),
+	)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := translator.TranslateText([]string{"Hello"}, "DE", deepl.WithSourceLang("EN"), deepl.WithFormality("more"))
+		if err != nil {
+			t.Fatalf("TranslateText: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("translations took %v, want no retry delays", elapsed)
+	}
+
+	// one attempt each for source and target languages
+	if n := len(client.attempts("/v2/languages")); n != 2 {
+		t.Errorf("got %d language requests, want 2", n)
+	}
+
+	// the embedded languages are still used for validation
+	_, err := translator.TranslateText([]string{"Hello"}, "XX")
+	if !errors.Is(err, deepl.ErrInvalidTranslateOptions) {
+		t.Errorf("got error %v, want ErrInvalidTranslateOptions", err)
+	}
+}
+
+func TestMetadataFallbackPrefersCached(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv, deepl.WithMetadataTTL(time.Nanosecond))
+
+	if !translator.SupportsFormality("DE") {
+		t.Fatal("DE does not support formality")
+	}
+
+	// the cached languages are stale and cannot be refreshed
+	srv.InjectFault(deepltest.Fault{Kind: deepltest.FaultServiceUnavailable, Endpoint: "v2/languages", Percent: 100})
+	time.Sleep(time.Millisecond)
+
+	if !translator.SupportsFormality("DE") {
+		t.Error("DE does not support formality using cached languages")
+	}
+}
@@ -0,0 +1,219 @@
+package deepl
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Metrics receives operational events of a Translator.
+//
+// Implementations must be safe for concurrent use. They can forward the
+// events to any monitoring system, e.g. Prometheus or OpenTelemetry, an
+// implementation publishing them via expvar is provided by ExpvarMetrics.
+//
+// Events are reported by route, i.e. the API endpoint with resource ids
+// replaced by placeholders, e.g. `v2/document/{id}/result`, so the number of
+// distinct routes is bounded.
+type Metrics interface {
+	// RequestCompleted is called after every attempt of an API call with the
+	// response status, or 0 if no response was received
+	RequestCompleted(route string, status int, latency time.Duration)
+	// RequestRetried is called before a failed attempt is retried
+	RequestRetried(route string, attempt int)
+	// CharactersBilled is called once for every successful text translation
+	// and every document translation reported as done with the number of
+	// billed characters. For text translations, these are reported by the API
+	// if requested using WithShowBilledCharacters, and counted locally
+	// otherwise.
+	CharactersBilled(route string, n int)
+	// CacheLookup is called for every text looked up in the translation cache
+	CacheLookup(hit bool)
+}
+
+// WithMetrics reports operational events of the translator to the given
+// metrics
+func WithMetrics(m Metrics) TranslatorOption {
+	return func(t *Translator) error {
+		t.metrics = m
+		return nil
+	}
+}
+
+// metricsMiddleware reports the completed requests to the given metrics
+func metricsMiddleware(m Metrics) Middleware {
+	return func(next HTTPClient) HTTPClient {
+		return HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			info, _ := RequestInfoFromContext(req.Context())
+
+			start := time.Now()
+			res, err := next.Do(req)
+
+			status := 0
+			if err == nil {
+				status = res.StatusCode
+			}
+			m.RequestCompleted(info.Route, status, time.Since(start))
+
+			return res, err
+		})
+	}
+}
+
+/*
+ *  EXPVAR
+ */
+
+// DefaultLatencyBuckets are the upper bounds in seconds of the latency
+// histogram buckets used by ExpvarMetrics.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// ExpvarMetrics collects metrics in expvar variables.
+//
+// The metrics are exposed as a map with the keys
+//   - `requests`: request counts by route and status
+//   - `retries`: retry counts by route
+//   - `billed_characters`: billed character counts by route
+//   - `cache_hits`, `cache_misses`: cache lookup counts
+//   - `latency_seconds`: latency histograms by route
+type ExpvarMetrics struct {
+	vars *expvar.Map
+
+	requests    *expvar.Map
+	retries     *expvar.Map
+	billed      *expvar.Map
+	cacheHits   *expvar.Int
+	cacheMisses *expvar.Int
+	latency     *expvar.Map
+
+	mu sync.Mutex
+}
+
+// NewExpvarMetrics creates metrics published under the given name, which must
+// be unique among expvar variables. If name is empty the metrics are not
+// published, they can still be accessed using Var.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		vars: new(expvar.Map).Init(),
+
+		requests:    new(expvar.Map).Init(),
+		retries:     new(expvar.Map).Init(),
+		billed:      new(expvar.Map).Init(),
+		cacheHits:   new(expvar.Int),
+		cacheMisses: new(expvar.Int),
+		latency:     new(expvar.Map).Init(),
+	}
+
+	m.vars.Set("requests", m.requests)
+	m.vars.Set("retries", m.retries)
+	m.vars.Set("billed_characters", m.billed)
+	m.vars.Set("cache_hits", m.cacheHits)
+	m.vars.Set("cache_misses", m.cacheMisses)
+	m.vars.Set("latency_seconds", m.latency)
+
+	if name != "" {
+		expvar.Publish(name, m.vars)
+	}
+
+	return m
+}
+
+// Var returns the map holding all metrics.
+func (m *ExpvarMetrics) Var() *expvar.Map {
+	return m.vars
+}
+
+func (m *ExpvarMetrics) RequestCompleted(route string, status int, latency time.Duration) {
+	key := "error"
+	if status != 0 {
+		key = strconv.Itoa(status)
+	}
+
+	m.mu.Lock()
+	byStatus, ok := m.requests.Get(route).(*expvar.Map)
+	if !ok {
+		byStatus = new(expvar.Map).Init()
+		m.requests.Set(route, byStatus)
+	}
+	h, ok := m.latency.Get(route).(*histogram)
+	if !ok {
+		h = newHistogram(DefaultLatencyBuckets)
+		m.latency.Set(route, h)
+	}
+	m.mu.Unlock()
+
+	byStatus.Add(key, 1)
+	h.observe(latency.Seconds())
+}
+
+func (m *ExpvarMetrics) RequestRetried(route string, attempt int) {
+	m.retries.Add(route, 1)
+}
+
+func (m *ExpvarMetrics) CharactersBilled(route string, n int) {
+	m.billed.Add(route, int64(n))
+}
+
+func (m *ExpvarMetrics) CacheLookup(hit bool) {
+	if hit {
+		m.cacheHits.Add(1)
+	} else {
+		m.cacheMisses.Add(1)
+	}
+}
+
+// histogram is an expvar.Var counting observations in cumulative buckets
+type histogram struct {
+	bounds []float64
+
+	mu     sync.Mutex
+	counts []int64
+	count  int64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]int64, len(bounds)),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// String implements expvar.Var
+func (h *histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make(map[string]int64, len(h.bounds)+1)
+	for i, bound := range h.bounds {
+		buckets[strconv.FormatFloat(bound, 'g', -1, 64)] = h.counts[i]
+	}
+	buckets["+Inf"] = h.count
+
+	data, _ := json.Marshal(struct {
+		Buckets map[string]int64 `json:"buckets"`
+		Count   int64            `json:"count"`
+		Sum     float64          `json:"sum"`
+	}{
+		Buckets: buckets,
+		Count:   h.count,
+		Sum:     h.sum,
+	})
+	return string(data)
+}
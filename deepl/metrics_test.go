@@ -0,0 +1,77 @@
+package deepl_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+// keys returns the keys of the expvar map with the given name
+func keys(t *testing.T, m *deepl.ExpvarMetrics, name string) map[string]json.RawMessage {
+	t.Helper()
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(m.Var().Get(name).String()), &values); err != nil {
+		t.Fatalf("error decoding %s: %v", name, err)
+	}
+	return values
+}
+
+func TestMetricsByRoute(t *testing.T) {
+	srv := deepltest.NewServer(
+		deepltest.WithDocumentPolls(0),
+		deepltest.WithFaults(deepltest.Fault{
+			Kind:     deepltest.FaultServiceUnavailable,
+			Endpoint: "v2/translate",
+			Nth:      1,
+		}),
+	)
+	defer srv.Close()
+
+	metrics := deepl.NewExpvarMetrics("")
+	translator := newTestTranslator(t, srv, deepl.WithMetrics(metrics), fastRetries())
+	ctx := context.Background()
+
+	for _, text := range []string{"first", "second", "third"} {
+		if _, err := translator.TranslateDocument(ctx, strings.NewReader(text), "doc.txt", &strings.Builder{}, "DE"); err != nil {
+			t.Fatalf("TranslateDocument: %v", err)
+		}
+	}
+	if _, err := translator.TranslateText([]string{"Hello"}, "DE"); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	if _, err := translator.TranslateText([]string{"Hello"}, "DE", deepl.WithShowBilledCharacters(true)); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	// documents share their routes regardless of their ids
+	requests := keys(t, metrics, "requests")
+	for _, route := range []string{"v2/translate", "v2/document", "v2/document/{id}", "v2/document/{id}/result"} {
+		if _, ok := requests[route]; !ok {
+			t.Errorf("no requests for route %s", route)
+		}
+	}
+	if len(requests) != 4 {
+		t.Errorf("got requests for %d routes, want 4: %v", len(requests), requests)
+	}
+	if latency := keys(t, metrics, "latency_seconds"); len(latency) != 4 {
+		t.Errorf("got latencies for %d routes, want 4", len(latency))
+	}
+
+	if got := string(keys(t, metrics, "retries")["v2/translate"]); got != "1" {
+		t.Errorf("got %s retries, want 1", got)
+	}
+
+	// the retried attempt is not billed
+	billed := keys(t, metrics, "billed_characters")
+	if got := string(billed["v2/translate"]); got != "10" {
+		t.Errorf("got %s billed characters for texts, want 10", got)
+	}
+	if got := string(billed["v2/document/{id}"]); got != "16" {
+		t.Errorf("got %s billed characters for documents, want 16", got)
+	}
+}
@@ -0,0 +1,142 @@
+package deepl
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPClientFunc is an adapter to allow the use of ordinary functions as
+// HTTPClient.
+type HTTPClientFunc func(*http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f HTTPClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the client sending API requests, e.g. to inspect or modify
+// requests and responses. It is called for every attempt of a request.
+type Middleware func(next HTTPClient) HTTPClient
+
+// WithMiddleware adds middlewares around the http client, the first one given
+// is the outermost
+func WithMiddleware(m ...Middleware) TranslatorOption {
+	return func(t *Translator) error {
+		t.middlewares = append(t.middlewares, m...)
+		return nil
+	}
+}
+
+// RequestInfo describes an attempt of an API call.
+type RequestInfo struct {
+	// The API endpoint, e.g. `v2/document/4F6E.../result`
+	Endpoint string
+	// The API endpoint with resource ids replaced by placeholders, e.g.
+	// `v2/document/{id}/result`, suitable to group requests by
+	Route string
+	// The attempt number, starting at 1
+	Attempt int
+	// The number of characters to translate, 0 for requests other than text
+	// translations
+	Characters int
+}
+
+type requestInfoKey struct{}
+
+type requestCharactersKey struct{}
+
+// RequestInfoFromContext returns the information about the API call a
+// request belongs to, middlewares can obtain it from the request context.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// endpointRoute returns the route of the given endpoint, replacing the ids of
+// documents and glossaries by a placeholder
+func endpointRoute(endpoint string) string {
+	parts := strings.Split(endpoint, "/")
+	if len(parts) > 2 && (parts[1] == "document" || parts[1] == "glossaries") {
+		parts[2] = "{id}"
+		return strings.Join(parts, "/")
+	}
+	return endpoint
+}
+
+// withRequestCharacters records the number of characters to translate in the
+// context of an API call
+func withRequestCharacters(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, requestCharactersKey{}, n)
+}
+
+func requestCharacters(ctx context.Context) int {
+	n, _ := ctx.Value(requestCharactersKey{}).(int)
+	return n
+}
+
+// chainMiddleware wraps the client with the given middlewares
+func chainMiddleware(client HTTPClient, middlewares []Middleware) HTTPClient {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		client = middlewares[i](client)
+	}
+	return client
+}
+
+// LoggingMiddleware logs API requests and responses using the given logger.
+//
+// Sent requests are logged at debug level, responses at info level, or warn
+// level if their status is not successful, and failed requests at error level.
+// The `Authorization` header is always redacted.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next HTTPClient) HTTPClient {
+		return HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			info, _ := RequestInfoFromContext(ctx)
+
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("endpoint", info.Endpoint),
+				slog.Int("attempt", info.Attempt),
+			}
+			if info.Characters > 0 {
+				attrs = append(attrs, slog.Int("characters", info.Characters))
+			}
+
+			if logger.Enabled(ctx, slog.LevelDebug) {
+				logger.LogAttrs(ctx, slog.LevelDebug, "sending request",
+					append(attrs, slog.Any("header", redactHeader(req.Header)))...)
+			}
+
+			start := time.Now()
+			res, err := next.Do(req)
+			attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelError, "request failed",
+					append(attrs, slog.String("error", err.Error()))...)
+				return nil, err
+			}
+
+			level := slog.LevelInfo
+			if res.StatusCode >= 300 {
+				level = slog.LevelWarn
+			}
+			logger.LogAttrs(ctx, level, "received response",
+				append(attrs, slog.Int("status", res.StatusCode))...)
+
+			return res, nil
+		})
+	}
+}
+
+// redactHeader returns a copy of the header with credentials redacted
+func redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	if redacted.Get("Authorization") != "" {
+		redacted.Set("Authorization", "REDACTED")
+	}
+	return redacted
+}
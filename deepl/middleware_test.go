@@ -0,0 +1,115 @@
+package deepl_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLoggingMiddlewareRedactsAuthKey(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:     deepltest.FaultServiceUnavailable,
+		Endpoint: "v2/translate",
+		Nth:      1,
+	}))
+	defer srv.Close()
+
+	var out syncBuffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	translator := newTestTranslator(t, srv, fastRetries(), deepl.WithMiddleware(deepl.LoggingMiddleware(logger)))
+
+	if _, err := translator.TranslateText([]string{"Hello"}, "DE"); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	logs := out.String()
+	if strings.Contains(logs, srv.AuthKey) {
+		t.Errorf("auth key %q was logged:\n%s", srv.AuthKey, logs)
+	}
+	if !strings.Contains(logs, "REDACTED") {
+		t.Errorf("headers were not logged:\n%s", logs)
+	}
+	for _, msg := range []string{`"sending request"`, `"received response"`, `"status":503`, `"status":200`, `"attempt":2`} {
+		if !strings.Contains(logs, msg) {
+			t.Errorf("missing %s in logs:\n%s", msg, logs)
+		}
+	}
+}
+
+func TestRequestInfoFromContext(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:     deepltest.FaultServiceUnavailable,
+		Endpoint: "v2/glossaries",
+		Nth:      1,
+	}))
+	defer srv.Close()
+
+	var (
+		mu    sync.Mutex
+		infos []deepl.RequestInfo
+	)
+	record := func(next deepl.HTTPClient) deepl.HTTPClient {
+		return deepl.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			info, ok := deepl.RequestInfoFromContext(req.Context())
+			if !ok {
+				return nil, errors.New("no request info")
+			}
+			mu.Lock()
+			infos = append(infos, info)
+			mu.Unlock()
+			return next.Do(req)
+		})
+	}
+	translator := newTestTranslator(t, srv, fastRetries(), deepl.WithMiddleware(record))
+
+	if _, err := translator.TranslateText([]string{"Hello", "World"}, "DE"); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	glossary, err := translator.CreateGlossary("greetings", "EN", "DE", []deepl.GlossaryEntry{{Source: "Hello", Target: "Hallo"}})
+	if err != nil {
+		t.Fatalf("CreateGlossary: %v", err)
+	}
+	if _, err := translator.GetGlossary(glossary.GlossaryId); err != nil {
+		t.Fatalf("GetGlossary: %v", err)
+	}
+
+	want := []deepl.RequestInfo{
+		{Endpoint: "v2/translate", Route: "v2/translate", Attempt: 1, Characters: 10},
+		{Endpoint: "v2/glossaries", Route: "v2/glossaries", Attempt: 1},
+		{Endpoint: "v2/glossaries", Route: "v2/glossaries", Attempt: 2},
+		{Endpoint: "v2/glossaries/" + glossary.GlossaryId, Route: "v2/glossaries/{id}", Attempt: 1},
+	}
+	if len(infos) != len(want) {
+		t.Fatalf("got request infos %+v, want %+v", infos, want)
+	}
+	for i := range want {
+		if infos[i] != want[i] {
+			t.Errorf("got request info %+v, want %+v", infos[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package deepl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TargetsError is returned by TranslateToMany if the translation into some of
+// the target languages failed.
+type TargetsError struct {
+	// The errors keyed by target language
+	Errors map[string]error
+	// The total number of target languages
+	Total int
+}
+
+func (e *TargetsError) Error() string {
+	targets := e.Targets()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to translate into %d of %d target languages", len(targets), e.Total)
+	if len(targets) > 0 {
+		fmt.Fprintf(&b, ": %s: %v", targets[0], e.Errors[targets[0]])
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the underlying errors ordered by target language.
+func (e *TargetsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, target := range e.Targets() {
+		errs = append(errs, e.Errors[target])
+	}
+	return errs
+}
+
+// Targets returns the sorted target languages that failed.
+func (e *TargetsError) Targets() []string {
+	targets := make([]string, 0, len(e.Errors))
+	for target := range e.Errors {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// WithGlossaries specifies glossaries to use depending on the language pair
+// of the translation, keyed by source and target language, e.g.
+// `{SourceLang: "EN", TargetLang: "DE"}`. Regional variants of the target
+// language are ignored when looking up the glossary.
+//
+// A glossary is only selected if the source language is set using
+// WithSourceLang and no glossary is set using WithGlossaryID.
+func WithGlossaries(glossaries map[LanguagePair]string) TranslateOption {
+	return func(o *TranslateOptions) error {
+		o.Glossaries = glossaries
+		return nil
+	}
+}
+
+// resolveGlossary selects the glossary for the given target language from
+// the glossaries given by WithGlossaries
+func (o *TranslateOptions) resolveGlossary(targetLang string) {
+	glossaries := o.Glossaries
+	o.Glossaries = nil
+
+	if o.GlossaryID != nil || o.SourceLang == nil {
+		return
+	}
+
+	source, target := baseLangCodeOf(*o.SourceLang), baseLangCodeOf(targetLang)
+	for pair, id := range glossaries {
+		if strings.EqualFold(baseLangCodeOf(pair.SourceLang), source) && strings.EqualFold(baseLangCodeOf(pair.TargetLang), target) {
+			o.GlossaryID = &id
+			return
+		}
+	}
+}
+
+// TranslateToMany translates the given texts into each of the target
+// languages and returns the translations keyed by target language.
+//
+// Each target language is translated like TranslateTextBatch, with the
+// maximum concurrency set by WithMaxConcurrency applying to the requests of
+// all target languages combined. Glossaries given by
+// WithGlossaries are selected per language pair. If the translation into some
+// of the target languages fails, the translations into the remaining ones
+// are returned along with a *TargetsError.
+func (t *Translator) TranslateToMany(ctx context.Context, texts []string, targets []string, opts ...TranslateOption) (map[string][]Translation, error) {
+	var options TranslateOptions
+	if err := options.Gather(opts...); err != nil {
+		return nil, fmt.Errorf("error setting translate option: %w", err)
+	}
+
+	var (
+		results = make(map[string][]Translation, len(targets))
+		errs    = make(map[string]error)
+
+		mu sync.Mutex
+		wg sync.WaitGroup
+		// limits the requests of all target languages
+		sem = make(chan struct{}, t.maxConcurrency)
+
+		seen = make(map[string]bool, len(targets))
+	)
+	for _, target := range targets {
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+
+		wg.Add(1)
+		go func(target string) {
+			defer wg.Done()
+
+			ts, err := t.translateTextBatch(ctx, sem, texts, target, opts...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[target] = err
+			} else {
+				results[target] = ts
+			}
+		}(target)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, &TargetsError{Errors: errs, Total: len(seen)}
+	}
+
+	return results, nil
+}
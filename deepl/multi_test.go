@@ -0,0 +1,81 @@
+package deepl_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+// concurrencyClient tracks the peak number of concurrent requests
+type concurrencyClient struct {
+	client deepl.HTTPClient
+
+	mu       sync.Mutex
+	inflight int
+	peak     int
+}
+
+func (c *concurrencyClient) Do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	c.inflight++
+	if c.inflight > c.peak {
+		c.peak = c.inflight
+	}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.inflight--
+		c.mu.Unlock()
+	}()
+
+	return c.client.Do(req)
+}
+
+func TestTranslateToMany(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:     deepltest.FaultSlow,
+		Endpoint: "v2/translate",
+		Percent:  100,
+		Delay:    20 * time.Millisecond,
+	}))
+	defer srv.Close()
+
+	client := &concurrencyClient{client: srv.Client()}
+	translator := newTestTranslator(t, srv, deepl.WithHTTPClient(client), deepl.WithMaxConcurrency(4))
+
+	// every target language requires multiple requests
+	texts := make([]string, 3*deepl.MaxTextsPerRequest)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+	targets := []string{"DE", "FR", "ES", "IT"}
+
+	results, err := translator.TranslateToMany(context.Background(), texts, targets)
+	if err != nil {
+		t.Fatalf("TranslateToMany: %v", err)
+	}
+
+	for _, target := range targets {
+		ts := results[target]
+		if len(ts) != len(texts) {
+			t.Fatalf("got %d %s translations, want %d", len(ts), target, len(texts))
+		}
+		for i, tr := range ts {
+			if want := deepltest.Translate(texts[i], target); tr.Text != want {
+				t.Errorf("got %s translation %q, want %q", target, tr.Text, want)
+			}
+		}
+	}
+
+	// the limit applies to the requests of all target languages combined
+	if client.peak != 4 {
+		t.Errorf("got peak concurrency %d, want 4", client.peak)
+	}
+}
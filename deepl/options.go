@@ -1,7 +1,11 @@
 package deepl
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
 )
 
 type TranslateOptions struct {
@@ -15,6 +19,13 @@ type TranslateOptions struct {
 	NonSplittingTags   []*string `json:"non_splitting_tags,omitempty"`
 	SplittingTags      []*string `json:"splitting_tags,omitempty"`
 	IgnoreTags         []*string `json:"ignore_tags,omitempty"`
+
+	Context              *string `json:"context,omitempty"`
+	ShowBilledCharacters *bool   `json:"show_billed_characters,omitempty"`
+	ModelType            *string `json:"model_type,omitempty"`
+
+	// Glossaries by language pair, resolved into GlossaryID before sending
+	Glossaries map[LanguagePair]string `json:"-"`
 }
 
 func (o *TranslateOptions) Gather(opts ...TranslateOption) error {
@@ -286,6 +297,169 @@ func WithIgnoreTags(value []string) TranslateOption {
 	}
 }
 
+// WithTranslationContext specifies additional text that influences the translation
+// without being translated itself.
+//
+// Characters included in the `context` parameter are not counted toward
+// billing.
+func WithTranslationContext(value string) TranslateOption {
+	return func(o *TranslateOptions) error {
+		o.Context = &value
+		return nil
+	}
+}
+
+// WithShowBilledCharacters sets whether the response should include the number
+// of characters billed for each translation, see Translation.BilledCharacters.
+func WithShowBilledCharacters(value bool) TranslateOption {
+	return func(o *TranslateOptions) error {
+		o.ShowBilledCharacters = &value
+		return nil
+	}
+}
+
+// WithModelType specifies which kind of translation model to use.
+//
+// Possible values are:
+//   - `quality_optimized` - use the model with the highest translation quality
+//   - `prefer_quality_optimized` - use the highest quality model if available
+//     for the language pair, otherwise fallback to the latency optimized one
+//   - `latency_optimized` - use the model with the lowest response latency
+//
+// The model used is reported in Translation.ModelTypeUsed.
+func WithModelType(value string) TranslateOption {
+	return func(o *TranslateOptions) error {
+		switch value {
+		case "quality_optimized", "prefer_quality_optimized", "latency_optimized":
+			o.ModelType = &value
+			return nil
+		}
+		return translateOptionInvalidValueError("model_type", value)
+	}
+}
+
+// DocumentOptions holds the parameters of a document translation.
+type DocumentOptions struct {
+	SourceLang   *string
+	Formality    *string
+	GlossaryID   *string
+	OutputFormat *string
+	Filename     *string
+}
+
+func (o *DocumentOptions) Gather(opts ...DocumentOption) error {
+	for _, option := range opts {
+		if err := option.applyDocumentOption(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DocumentOption can be used to customize document translations.
+//
+// Besides the document specific options, the TranslateOption values
+// WithSourceLang, WithFormality and WithGlossaryID can be used.
+type DocumentOption interface {
+	applyDocumentOption(*DocumentOptions) error
+}
+
+type documentOptionFunc func(*DocumentOptions) error
+
+func (f documentOptionFunc) applyDocumentOption(o *DocumentOptions) error {
+	return f(o)
+}
+
+func (f TranslateOption) applyDocumentOption(o *DocumentOptions) error {
+	var options TranslateOptions
+	if err := f(&options); err != nil {
+		return err
+	}
+
+	if options.SourceLang != nil {
+		o.SourceLang = options.SourceLang
+	}
+	if options.Formality != nil {
+		o.Formality = options.Formality
+	}
+	if options.GlossaryID != nil {
+		o.GlossaryID = options.GlossaryID
+	}
+
+	options.SourceLang, options.Formality, options.GlossaryID = nil, nil, nil
+	if !reflect.DeepEqual(options, TranslateOptions{}) {
+		return errors.New("option is not supported for document translations")
+	}
+
+	return nil
+}
+
+// WithOutputFormat specifies the file format of the translated document, if
+// it should differ from the format of the uploaded document.
+//
+// Currently, PDF documents can be converted to DOCX, and HTML and XLIFF
+// documents to the alternative file extension of their format, i.e. between
+// `htm` and `html` or `xlf` and `xliff`.
+func WithOutputFormat(value string) DocumentOption {
+	return documentOptionFunc(func(o *DocumentOptions) error {
+		value = strings.ToLower(strings.TrimPrefix(value, "."))
+		if _, ok := documentFormats[value]; !ok {
+			return translateOptionInvalidValueError("output_format", value)
+		}
+		o.OutputFormat = &value
+		return nil
+	})
+}
+
+// WithFilename overrides the filename of the uploaded document, which
+// determines the document format.
+func WithFilename(value string) DocumentOption {
+	return documentOptionFunc(func(o *DocumentOptions) error {
+		if value == "" {
+			return translateOptionInvalidValueError("filename", value)
+		}
+		o.Filename = &value
+		return nil
+	})
+}
+
+// documentFormats maps the supported document formats to the formats they can
+// be converted to, in addition to themselves
+var documentFormats = map[string][]string{
+	"docx":  nil,
+	"pptx":  nil,
+	"xlsx":  nil,
+	"pdf":   {"docx"},
+	"htm":   {"html"},
+	"html":  {"htm"},
+	"txt":   nil,
+	"xlf":   {"xliff"},
+	"xliff": {"xlf"},
+	"srt":   nil,
+}
+
+// validateDocumentFormat checks whether the document with the given filename
+// is supported and can be converted to the given output format, if any
+func validateDocumentFormat(filename string, outputFormat *string) error {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+
+	conversions, ok := documentFormats[format]
+	if !ok {
+		return fmt.Errorf("unsupported document format: %q", format)
+	}
+
+	if outputFormat == nil || *outputFormat == format {
+		return nil
+	}
+	for _, f := range conversions {
+		if f == *outputFormat {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported document conversion: %s to %s", format, *outputFormat)
+}
+
 func translateOptionInvalidValueError(name string, value string) error {
 	return fmt.Errorf("Invalid value for option `%s`: %s", name, value)
 }
@@ -0,0 +1,461 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	defaultEjectionDuration = 5 * time.Minute
+	// poolDocumentRetention is how long the pool remembers the translator of
+	// an uploaded document that is neither downloaded nor failed
+	poolDocumentRetention = 24 * time.Hour
+)
+
+// ErrNoTranslatorAvailable is returned by a TranslatorPool if all of its
+// translators are ejected.
+var ErrNoTranslatorAvailable = errors.New("no translator available")
+
+// TranslatorPool routes requests across multiple translators, e.g. using
+// different authentication keys.
+//
+// Translators are selected round-robin, or weighted if configured using
+// WithPoolWeights. If a request fails because the quota or the budget set
+// using WithBudget of the translator is exceeded or its authorization failed,
+// the translator is ejected from the pool for some time and the request is
+// retried with the next one.
+//
+// Glossaries belong to a single account, so glossary ids are only valid for
+// one translator of the pool. Likewise, documents uploaded using the pool can
+// only be handled by the pool until they are downloaded, their translation
+// failed or 24 hours have passed.
+type TranslatorPool struct {
+	ejectionDuration time.Duration
+
+	mu        sync.Mutex
+	members   []*poolMember
+	documents map[string]poolDocument
+}
+
+// poolDocument is a document uploaded using one of the translators of a pool
+type poolDocument struct {
+	owner    *Translator
+	uploaded time.Time
+}
+
+type poolMember struct {
+	translator *Translator
+	weight     int
+
+	// current weight for smooth weighted round-robin selection
+	current      int
+	ejectedUntil time.Time
+}
+
+// PoolOption is a functional option for configuring the TranslatorPool
+type PoolOption func(*TranslatorPool) error
+
+// WithPoolWeights sets the relative share of requests routed to each
+// translator, in the order the translators were given
+func WithPoolWeights(weights ...int) PoolOption {
+	return func(p *TranslatorPool) error {
+		if len(weights) != len(p.members) {
+			return fmt.Errorf("got %d weights for %d translators", len(weights), len(p.members))
+		}
+		for i, w := range weights {
+			if w < 1 {
+				return errors.New("pool weights must be positive")
+			}
+			p.members[i].weight = w
+		}
+		return nil
+	}
+}
+
+// WithEjectionDuration sets for how long a translator is not used after its
+// quota or budget was exceeded or its authorization failed
+func WithEjectionDuration(d time.Duration) PoolOption {
+	return func(p *TranslatorPool) error {
+		if d < 0 {
+			return errors.New("ejection duration must be non-negative")
+		}
+		p.ejectionDuration = d
+		return nil
+	}
+}
+
+// NewTranslatorPool creates a pool routing requests across the given
+// translators
+func NewTranslatorPool(translators []*Translator, opts ...PoolOption) (*TranslatorPool, error) {
+	if len(translators) == 0 {
+		return nil, errors.New("translator pool requires at least one translator")
+	}
+
+	p := &TranslatorPool{
+		ejectionDuration: defaultEjectionDuration,
+		documents:        make(map[string]poolDocument),
+	}
+	for _, t := range translators {
+		if t == nil {
+			return nil, errors.New("translator must not be nil")
+		}
+		p.members = append(p.members, &poolMember{translator: t, weight: 1})
+	}
+
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// Healthy returns the number of translators that are currently not ejected.
+func (p *TranslatorPool) Healthy() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	n := 0
+	for _, m := range p.members {
+		if !now.Before(m.ejectedUntil) {
+			n++
+		}
+	}
+	return n
+}
+
+// do calls fn with the next translator, failing over to the remaining ones if
+// it fails because of the quota, budget or authorization
+func (p *TranslatorPool) do(ctx context.Context, fn func(*Translator) error) error {
+	tried := make(map[*poolMember]bool, len(p.members))
+
+	var lastErr error
+	for {
+		m := p.pick(tried)
+		if m == nil {
+			if lastErr != nil {
+				return fmt.Errorf("%w: %w", ErrNoTranslatorAvailable, lastErr)
+			}
+			return ErrNoTranslatorAvailable
+		}
+		tried[m] = true
+
+		err := fn(m.translator)
+		if err == nil || !isFailoverError(err) {
+			return err
+		}
+
+		p.eject(m)
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// pick selects the next healthy translator that was not tried yet using
+// smooth weighted round-robin
+func (p *TranslatorPool) pick(tried map[*poolMember]bool) *poolMember {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+
+	var (
+		best  *poolMember
+		total int
+	)
+	for _, m := range p.members {
+		if tried[m] || now.Before(m.ejectedUntil) {
+			continue
+		}
+		m.current += m.weight
+		total += m.weight
+		if best == nil || m.current > best.current {
+			best = m
+		}
+	}
+	if best != nil {
+		best.current -= total
+	}
+
+	return best
+}
+
+func (p *TranslatorPool) eject(m *poolMember) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m.ejectedUntil = time.Now().Add(p.ejectionDuration)
+	m.current = 0
+}
+
+// isFailoverError reports whether a request failing with the given error
+// should be retried with another translator
+func isFailoverError(err error) bool {
+	return errors.Is(err, ErrQuotaExceeded) || errors.Is(err, ErrAuthFailed) || errors.Is(err, ErrBudgetExceeded)
+}
+
+// TranslateText translates the given text(s) into the specified target
+// language using one of the translators of the pool.
+func (p *TranslatorPool) TranslateText(text []string, targetLang string, opts ...TranslateOption) ([]Translation, error) {
+	return p.TranslateTextContext(context.Background(), text, targetLang, opts...)
+}
+
+// TranslateTextContext is like TranslateText but uses the given context for
+// the underlying requests.
+func (p *TranslatorPool) TranslateTextContext(ctx context.Context, text []string, targetLang string, opts ...TranslateOption) ([]Translation, error) {
+	var translations []Translation
+	err := p.do(ctx, func(t *Translator) error {
+		var err error
+		translations, err = t.TranslateTextContext(ctx, text, targetLang, opts...)
+		return err
+	})
+	return translations, err
+}
+
+// TranslateToMany is like Translator.TranslateToMany, target languages that
+// fail because of the quota, budget or authorization are retried with the next
+// translator of the pool.
+func (p *TranslatorPool) TranslateToMany(ctx context.Context, texts []string, targets []string, opts ...TranslateOption) (map[string][]Translation, error) {
+	var (
+		results   = make(map[string][]Translation, len(targets))
+		failed    = make(map[string]error)
+		remaining = targets
+	)
+	err := p.do(ctx, func(t *Translator) error {
+		res, err := t.TranslateToMany(ctx, texts, remaining, opts...)
+		for target, ts := range res {
+			results[target] = ts
+		}
+
+		var targetsErr *TargetsError
+		if !errors.As(err, &targetsErr) {
+			return err
+		}
+
+		// only fail over the target languages that can succeed elsewhere
+		remaining = nil
+		for target, err := range targetsErr.Errors {
+			if isFailoverError(err) {
+				remaining = append(remaining, target)
+			} else {
+				failed[target] = err
+			}
+		}
+		if len(remaining) > 0 {
+			return err
+		}
+		return nil
+	})
+
+	var targetsErr *TargetsError
+	if errors.As(err, &targetsErr) {
+		for target, err := range targetsErr.Errors {
+			failed[target] = err
+		}
+	} else if err != nil {
+		return results, err
+	}
+
+	if len(failed) > 0 {
+		return results, &TargetsError{Errors: failed, Total: len(results) + len(failed)}
+	}
+	return results, nil
+}
+
+// GetUsage retrieves the usage information of all translators of the pool and
+// returns their sum. If it cannot be retrieved for some of the translators,
+// the sum of the remaining ones is returned along with the errors.
+func (p *TranslatorPool) GetUsage() (*Usage, error) {
+	return p.GetUsageContext(context.Background())
+}
+
+// GetUsageContext is like GetUsage but uses the given context for the
+// underlying requests.
+func (p *TranslatorPool) GetUsageContext(ctx context.Context) (*Usage, error) {
+	var (
+		total Usage
+		errs  []error
+	)
+	for _, m := range p.members {
+		u, err := m.translator.GetUsageContext(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		total.CharacterCount += u.CharacterCount
+		total.CharacterLimit += u.CharacterLimit
+		total.DocumentCount += u.DocumentCount
+		total.DocumentLimit += u.DocumentLimit
+		total.TeamDocumentCount += u.TeamDocumentCount
+		total.TeamDocumentLimit += u.TeamDocumentLimit
+	}
+
+	return &total, errors.Join(errs...)
+}
+
+// GetLanguagesContext retrieves the supported languages using one of the
+// translators of the pool.
+func (p *TranslatorPool) GetLanguagesContext(ctx context.Context, langType string) ([]Language, error) {
+	var languages []Language
+	err := p.do(ctx, func(t *Translator) error {
+		var err error
+		languages, err = t.GetLanguagesContext(ctx, langType)
+		return err
+	})
+	return languages, err
+}
+
+// TranslateDocumentUploadContext uploads the document at the given path using
+// one of the translators of the pool. The status and result of the document
+// are retrieved using the same translator.
+func (p *TranslatorPool) TranslateDocumentUploadContext(ctx context.Context, path string, targetLang string, opts ...DocumentOption) (*DocumentInfo, error) {
+	var doc *DocumentInfo
+	err := p.do(ctx, func(t *Translator) error {
+		var err error
+		doc, err = t.TranslateDocumentUploadContext(ctx, path, targetLang, opts...)
+		if err == nil {
+			p.setDocumentOwner(doc.DocumentId, t)
+		}
+		return err
+	})
+	return doc, err
+}
+
+// TranslateDocumentUploadReader uploads the document read from r using one of
+// the translators of the pool. The status and result of the document are
+// retrieved using the same translator.
+//
+// Unless r implements io.Seeker, the whole document is buffered in memory to
+// allow failing over to another translator.
+func (p *TranslatorPool) TranslateDocumentUploadReader(ctx context.Context, r io.Reader, filename string, contentType string, targetLang string, opts ...DocumentOption) (*DocumentInfo, error) {
+	doc, _, err := p.uploadDocument(ctx, r, filename, contentType, targetLang, opts...)
+	return doc, err
+}
+
+// TranslateDocumentStatusContext retrieves the status of a document uploaded
+// using the pool.
+func (p *TranslatorPool) TranslateDocumentStatusContext(ctx context.Context, id string, key string) (*DocumentStatus, error) {
+	t, err := p.documentOwner(id)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := t.TranslateDocumentStatusContext(ctx, id, key)
+	if (err == nil && status.Failed()) || errors.Is(err, ErrNotFound) {
+		p.forgetDocument(id)
+	}
+	return status, err
+}
+
+// TranslateDocumentDownloadContext downloads the result of a document
+// uploaded using the pool.
+func (p *TranslatorPool) TranslateDocumentDownloadContext(ctx context.Context, id string, key string) (*io.PipeReader, error) {
+	t, err := p.documentOwner(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := t.TranslateDocumentDownloadContext(ctx, id, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// the result can only be downloaded once
+	p.forgetDocument(id)
+
+	return r, nil
+}
+
+// TranslateDocument is like Translator.TranslateDocument using one of the
+// translators of the pool.
+func (p *TranslatorPool) TranslateDocument(ctx context.Context, in io.Reader, filename string, out io.Writer, targetLang string, opts ...DocumentOption) (int, error) {
+	doc, t, err := p.uploadDocument(ctx, in, filename, "", targetLang, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	p.forgetDocument(doc.DocumentId)
+
+	return t.finishDocument(ctx, doc, out)
+}
+
+// uploadDocument uploads the document using one of the translators, the
+// document is read again if the upload fails over to another translator
+func (p *TranslatorPool) uploadDocument(ctx context.Context, r io.Reader, filename string, contentType string, targetLang string, opts ...DocumentOption) (*DocumentInfo, *Translator, error) {
+	getBody, err := replayableBody(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading document: %w", err)
+	}
+
+	var (
+		doc   *DocumentInfo
+		owner *Translator
+	)
+	err = p.do(ctx, func(t *Translator) error {
+		body, err := getBody()
+		if err != nil {
+			return fmt.Errorf("error reading document: %w", err)
+		}
+
+		doc, err = t.TranslateDocumentUploadReader(ctx, body, filename, contentType, targetLang, opts...)
+		if err == nil {
+			owner = t
+			p.setDocumentOwner(doc.DocumentId, t)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doc, owner, nil
+}
+
+func (p *TranslatorPool) setDocumentOwner(id string, t *Translator) {
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for other, doc := range p.documents {
+		if now.Sub(doc.uploaded) > poolDocumentRetention {
+			delete(p.documents, other)
+		}
+	}
+	p.documents[id] = poolDocument{owner: t, uploaded: now}
+}
+
+func (p *TranslatorPool) forgetDocument(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.documents, id)
+}
+
+func (p *TranslatorPool) documentOwner(id string) (*Translator, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	doc, ok := p.documents[id]
+	if !ok {
+		return nil, fmt.Errorf("document %s was not uploaded using the pool: %w", id, ErrNotFound)
+	}
+	return doc.owner, nil
+}
+
+var (
+	_ MultiTargetTranslator = (*TranslatorPool)(nil)
+	_ DocumentTranslator    = (*TranslatorPool)(nil)
+	_ LanguageReader        = (*TranslatorPool)(nil)
+	_ UsageReader           = (*TranslatorPool)(nil)
+)
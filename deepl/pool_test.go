@@ -0,0 +1,260 @@
+package deepl_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+func newTestPool(t *testing.T, translators []*deepl.Translator, opts ...deepl.PoolOption) *deepl.TranslatorPool {
+	t.Helper()
+
+	p, err := deepl.NewTranslatorPool(translators, opts...)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	return p
+}
+
+func TestPoolFailover(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepares the server of the broken translator and returns its key
+		authKey func(srv *deepltest.Server) string
+	}{
+		{
+			name: "quota exceeded",
+			authKey: func(srv *deepltest.Server) string {
+				srv.SetCharacterCount(deepltest.DefaultCharacterLimit)
+				return srv.AuthKey
+			},
+		},
+		{
+			name: "authorization failed",
+			authKey: func(srv *deepltest.Server) string {
+				return "invalid"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brokenSrv := deepltest.NewServer()
+			defer brokenSrv.Close()
+			srv := deepltest.NewServer()
+			defer srv.Close()
+
+			client := &recordingClient{client: brokenSrv.Client()}
+			broken, err := deepl.NewTranslator(tt.authKey(brokenSrv),
+				deepl.WithServerURL(brokenSrv.URL),
+				deepl.WithHTTPClient(client),
+				deepl.WithoutRetries(),
+			)
+			if err != nil {
+				t.Fatalf("failed to create translator: %v", err)
+			}
+
+			p := newTestPool(t, []*deepl.Translator{broken, newTestTranslator(t, srv)})
+
+			for i := 0; i < 3; i++ {
+				translations, err := p.TranslateText([]string{"Hello"}, "DE")
+				if err != nil {
+					t.Fatalf("TranslateText: %v", err)
+				}
+				if want := deepltest.Translate("Hello", "DE"); translations[0].Text != want {
+					t.Errorf("got translation %q, want %q", translations[0].Text, want)
+				}
+			}
+
+			// the broken translator is ejected after the first failure
+			if n := len(client.attempts("/v2/translate")); n != 1 {
+				t.Errorf("got %d requests to the broken translator, want 1", n)
+			}
+			if n := p.Healthy(); n != 1 {
+				t.Errorf("got %d healthy translators, want 1", n)
+			}
+			if usage := srv.Usage(); usage.CharacterCount != 15 {
+				t.Errorf("got character count %d, want 15", usage.CharacterCount)
+			}
+		})
+	}
+}
+
+func TestPoolNoTranslatorAvailable(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithCharacterLimit(1))
+	defer srv.Close()
+
+	p := newTestPool(t, []*deepl.Translator{
+		newTestTranslator(t, srv, deepl.WithoutRetries()),
+		newTestTranslator(t, srv, deepl.WithoutRetries()),
+	})
+
+	_, err := p.TranslateText([]string{"Hello"}, "DE")
+	if !errors.Is(err, deepl.ErrNoTranslatorAvailable) || !errors.Is(err, deepl.ErrQuotaExceeded) {
+		t.Fatalf("got error %v, want ErrNoTranslatorAvailable wrapping ErrQuotaExceeded", err)
+	}
+
+	_, err = p.TranslateText([]string{"Hello"}, "DE")
+	if !errors.Is(err, deepl.ErrNoTranslatorAvailable) {
+		t.Fatalf("got error %v, want ErrNoTranslatorAvailable", err)
+	}
+}
+
+func TestPoolWeights(t *testing.T) {
+	srvA := deepltest.NewServer()
+	defer srvA.Close()
+	srvB := deepltest.NewServer()
+	defer srvB.Close()
+
+	p := newTestPool(t,
+		[]*deepl.Translator{newTestTranslator(t, srvA), newTestTranslator(t, srvB)},
+		deepl.WithPoolWeights(3, 1),
+	)
+
+	for i := 0; i < 40; i++ {
+		if _, err := p.TranslateText([]string{"x"}, "DE"); err != nil {
+			t.Fatalf("TranslateText: %v", err)
+		}
+	}
+
+	if a, b := srvA.Usage().CharacterCount, srvB.Usage().CharacterCount; a != 30 || b != 10 {
+		t.Errorf("got %d and %d requests, want 30 and 10", a, b)
+	}
+}
+
+func TestPoolTranslateToManyFailover(t *testing.T) {
+	// the first translator can only translate into one target language
+	srvA := deepltest.NewServer(deepltest.WithCharacterLimit(5))
+	defer srvA.Close()
+	srvB := deepltest.NewServer()
+	defer srvB.Close()
+
+	p := newTestPool(t, []*deepl.Translator{
+		newTestTranslator(t, srvA, deepl.WithMaxConcurrency(1), deepl.WithoutRetries()),
+		newTestTranslator(t, srvB),
+	})
+
+	results, err := p.TranslateToMany(context.Background(), []string{"Hello"}, []string{"DE", "FR", "EN"})
+
+	var targetsErr *deepl.TargetsError
+	if !errors.As(err, &targetsErr) {
+		t.Fatalf("got error %v, want *TargetsError", err)
+	}
+	if targets := targetsErr.Targets(); len(targets) != 1 || targets[0] != "EN" {
+		t.Errorf("got failed targets %v, want [EN]", targets)
+	}
+	if targetsErr.Total != 3 {
+		t.Errorf("got %d total targets, want 3", targetsErr.Total)
+	}
+
+	for _, target := range []string{"DE", "FR"} {
+		if ts := results[target]; len(ts) != 1 || ts[0].Text != deepltest.Translate("Hello", target) {
+			t.Errorf("got %s translations %+v", target, ts)
+		}
+	}
+
+	// only the target language failing with 456 is retried with the second
+	// translator, the unsupported one is not
+	if a, b := srvA.Usage().CharacterCount, srvB.Usage().CharacterCount; a != 5 || b != 5 {
+		t.Errorf("got character counts %d and %d, want 5 and 5", a, b)
+	}
+}
+
+func TestPoolDocumentAffinity(t *testing.T) {
+	srvA := deepltest.NewServer()
+	defer srvA.Close()
+	srvB := deepltest.NewServer()
+	defer srvB.Close()
+
+	ctx := context.Background()
+	translatorA, translatorB := newTestTranslator(t, srvA), newTestTranslator(t, srvB)
+
+	// advance the document ids of the second server, the servers would assign
+	// the same ones otherwise
+	if _, err := translatorB.TranslateDocumentUploadReader(ctx, strings.NewReader("skip"), "skip.txt", "", "DE"); err != nil {
+		t.Fatalf("TranslateDocumentUploadReader: %v", err)
+	}
+
+	p := newTestPool(t, []*deepl.Translator{translatorA, translatorB})
+
+	// the documents are uploaded to different translators
+	texts := []string{"first", "second"}
+	docs := make([]*deepl.DocumentInfo, len(texts))
+	for i, text := range texts {
+		doc, err := p.TranslateDocumentUploadReader(ctx, strings.NewReader(text), "doc.txt", "", "DE")
+		if err != nil {
+			t.Fatalf("TranslateDocumentUploadReader: %v", err)
+		}
+		docs[i] = doc
+	}
+	if a, b := srvA.Usage().DocumentCount, srvB.Usage().DocumentCount; a != 1 || b != 2 {
+		t.Fatalf("got document counts %d and %d, want 1 and 2", a, b)
+	}
+
+	for i, doc := range docs {
+		for {
+			status, err := p.TranslateDocumentStatusContext(ctx, doc.DocumentId, doc.DocumentKey)
+			if err != nil {
+				t.Fatalf("TranslateDocumentStatusContext: %v", err)
+			}
+			if status.Done() {
+				break
+			}
+		}
+
+		r, err := p.TranslateDocumentDownloadContext(ctx, doc.DocumentId, doc.DocumentKey)
+		if err != nil {
+			t.Fatalf("TranslateDocumentDownloadContext: %v", err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("error reading document: %v", err)
+		}
+		if want := deepltest.Translate(texts[i], "DE"); string(data) != want {
+			t.Errorf("got document %q, want %q", data, want)
+		}
+	}
+
+	var out bytes.Buffer
+	if _, err := p.TranslateDocument(ctx, strings.NewReader("third"), "doc.txt", &out, "DE"); err != nil {
+		t.Fatalf("TranslateDocument: %v", err)
+	}
+	if want := deepltest.Translate("third", "DE"); out.String() != want {
+		t.Errorf("got document %q, want %q", out.String(), want)
+	}
+}
+
+func TestPoolForgetsFailedDocuments(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithDocumentPolls(0))
+	defer srv.Close()
+
+	p := newTestPool(t, []*deepl.Translator{newTestTranslator(t, srv)})
+	ctx := context.Background()
+
+	// empty documents fail to translate
+	doc, err := p.TranslateDocumentUploadReader(ctx, strings.NewReader(""), "empty.txt", "", "DE")
+	if err != nil {
+		t.Fatalf("TranslateDocumentUploadReader: %v", err)
+	}
+
+	status, err := p.TranslateDocumentStatusContext(ctx, doc.DocumentId, doc.DocumentKey)
+	if err != nil {
+		t.Fatalf("TranslateDocumentStatusContext: %v", err)
+	}
+	if !status.Failed() {
+		t.Fatalf("got status %q, want error", status.Status)
+	}
+
+	// the pool no longer keeps track of the failed document
+	_, err = p.TranslateDocumentStatusContext(ctx, doc.DocumentId, doc.DocumentKey)
+	if !errors.Is(err, deepl.ErrNotFound) {
+		t.Errorf("got error %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,163 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// RateLimit configures the client-side rate limiting of API calls.
+type RateLimit struct {
+	// The number of requests per second, 0 means no limit
+	RequestsPerSecond float64
+	// The number of requests that can be sent at once, defaults to
+	// RequestsPerSecond rounded up
+	RequestBurst int
+	// The number of characters translated per minute, 0 means no limit
+	CharactersPerMinute int
+}
+
+// WithRateLimit limits the rate of API calls using token buckets shared by
+// all calls of the translator. Calls block until they are allowed to proceed
+// or their context is done.
+//
+// Every attempt of a request, including retries, counts towards the request
+// rate. The characters of text translations count towards the character
+// rate, a text exceeding the per minute limit on its own delays subsequent
+// ones accordingly.
+func WithRateLimit(l RateLimit) TranslatorOption {
+	return func(t *Translator) error {
+		if l.RequestsPerSecond < 0 || l.RequestBurst < 0 || l.CharactersPerMinute < 0 {
+			return errors.New("rate limits must be non-negative")
+		}
+
+		var limiter rateLimiter
+		if l.RequestsPerSecond > 0 {
+			burst := float64(l.RequestBurst)
+			if burst == 0 {
+				burst = math.Ceil(l.RequestsPerSecond)
+			}
+			limiter.requests = newTokenBucket(l.RequestsPerSecond, burst)
+		}
+		if l.CharactersPerMinute > 0 {
+			limiter.characters = newTokenBucket(float64(l.CharactersPerMinute)/60, float64(l.CharactersPerMinute))
+		}
+
+		t.limiter = &limiter
+		return nil
+	}
+}
+
+// rateLimiter limits the rate of requests and translated characters, a nil
+// limiter does not limit anything
+type rateLimiter struct {
+	requests   *tokenBucket
+	characters *tokenBucket
+}
+
+// waitRequest blocks until a request can be sent
+func (l *rateLimiter) waitRequest(ctx context.Context) error {
+	if l == nil || l.requests == nil {
+		return nil
+	}
+	return l.requests.wait(ctx, 1)
+}
+
+// waitCharacters blocks until the given texts can be translated
+func (l *rateLimiter) waitCharacters(ctx context.Context, texts []string) error {
+	if l == nil || l.characters == nil {
+		return nil
+	}
+
+	return l.characters.wait(ctx, float64(countCharacters(texts)))
+}
+
+// countCharacters returns the number of characters of the given texts
+func countCharacters(texts []string) int {
+	n := 0
+	for _, text := range texts {
+		n += utf8.RuneCountInString(text)
+	}
+	return n
+}
+
+// tokenBucket is a token bucket rate limiter. Tokens are reserved in the
+// order of calls, so waiting callers are served first come, first served.
+type tokenBucket struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	// the current time, replaceable for testing
+	now func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst float64) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  burst,
+		now:    time.Now,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait takes n tokens from the bucket, blocking until they are available or
+// the context is done
+func (b *tokenBucket) wait(ctx context.Context, n float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := b.reserve(n)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel(n)
+		return ctx.Err()
+	}
+}
+
+// reserve takes n tokens from the bucket, possibly going into debt, and
+// returns how long to wait until the debt is paid off
+func (b *tokenBucket) reserve(n float64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns the tokens of an abandoned reservation
+func (b *tokenBucket) cancel(n float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens = math.Min(b.tokens+n, b.burst)
+}
+
+// refill adds the tokens accumulated since the last refill, b.mu must be held
+func (b *tokenBucket) refill() {
+	now := b.now()
+	b.tokens = math.Min(b.tokens+now.Sub(b.last).Seconds()*b.rate, b.burst)
+	b.last = now
+}
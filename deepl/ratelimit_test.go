@@ -0,0 +1,91 @@
+package deepl
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestBucket(rate float64, burst float64) (*tokenBucket, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+
+	b := newTokenBucket(rate, burst)
+	b.now = clock.Now
+	b.last = clock.Now()
+	return b, clock
+}
+
+func checkDelay(t *testing.T, got time.Duration, want time.Duration) {
+	t.Helper()
+
+	if diff := got - want; diff < -time.Microsecond || diff > time.Microsecond {
+		t.Errorf("got delay %v, want %v", got, want)
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	b, clock := newTestBucket(10, 2)
+
+	// the burst is available at once
+	checkDelay(t, b.reserve(1), 0)
+	checkDelay(t, b.reserve(1), 0)
+	checkDelay(t, b.reserve(1), 100*time.Millisecond)
+
+	// a token is refilled every 100ms
+	clock.Advance(100 * time.Millisecond)
+	checkDelay(t, b.reserve(1), 100*time.Millisecond)
+
+	// tokens do not accumulate beyond the burst
+	clock.Advance(time.Minute)
+	checkDelay(t, b.reserve(2), 0)
+	checkDelay(t, b.reserve(1), 100*time.Millisecond)
+}
+
+func TestTokenBucketCharactersPerMinute(t *testing.T) {
+	// like WithRateLimit with 600 characters per minute
+	b, clock := newTestBucket(10, 600)
+
+	checkDelay(t, b.reserve(500), 0)
+	checkDelay(t, b.reserve(200), 10*time.Second)
+
+	// a reservation larger than the burst is delayed instead of refused
+	clock.Advance(10 * time.Second)
+	checkDelay(t, b.reserve(1200), 2*time.Minute)
+
+	// and delays subsequent ones accordingly
+	checkDelay(t, b.reserve(1), 2*time.Minute+100*time.Millisecond)
+}
+
+func TestTokenBucketCancel(t *testing.T) {
+	b, clock := newTestBucket(10, 1)
+
+	if err := b.wait(context.Background(), 1); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// the bucket is empty and the clock does not advance
+	if err := b.wait(ctx, 1); err != context.DeadlineExceeded {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// the tokens of the abandoned call are returned
+	checkDelay(t, b.reserve(1), 100*time.Millisecond)
+	clock.Advance(100 * time.Millisecond)
+	checkDelay(t, b.reserve(1), 100*time.Millisecond)
+}
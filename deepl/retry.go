@@ -0,0 +1,128 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/internal/retry"
+)
+
+// Backoff describes the exponentially increasing delay between retries.
+type Backoff = retry.Backoff
+
+// RetryPolicy configures how failed API calls are retried.
+type RetryPolicy struct {
+	// Maximum number of attempts including the first one, 1 disables retries
+	MaxAttempts int
+	// Delay between attempts, unless the server requests a specific delay.
+	// If the requested delay exceeds Backoff.MaxDelay, the call fails instead.
+	// An unset InitialDelay or Factor defaults to that of DefaultRetryPolicy,
+	// an unset MaxDelay means no limit.
+	Backoff Backoff
+	// Upper bound on the total time spent on all attempts, 0 means no limit
+	MaxElapsedTime time.Duration
+	// Reports whether a failed attempt should be retried, it is consulted for
+	// transport errors and responses with status 429 or 5xx, if nil only the
+	// latter are retried
+	RetryIf func(err error) bool
+	// Called with the number of the failed attempt, the delay until the next
+	// attempt and the error before waiting, e.g. for logging
+	OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+// DefaultRetryPolicy returns the retry policy used unless configured otherwise.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		Backoff: Backoff{
+			InitialDelay: 1 * time.Second,
+			MaxDelay:     120 * time.Second,
+			Factor:       1.6,
+			Jitter:       0.23,
+		},
+	}
+}
+
+// WithRetryPolicy allows overriding the default retry policy
+func WithRetryPolicy(p RetryPolicy) TranslatorOption {
+	return func(t *Translator) error {
+		if p.MaxAttempts < 1 {
+			return errors.New("retry policy must allow at least one attempt")
+		}
+
+		b := &p.Backoff
+		if b.InitialDelay < 0 || b.MaxDelay < 0 || b.Factor < 0 || b.Jitter < 0 || b.Jitter > 1 {
+			return errors.New("invalid retry backoff")
+		}
+		defaults := DefaultRetryPolicy().Backoff
+		if b.InitialDelay == 0 {
+			b.InitialDelay = defaults.InitialDelay
+		}
+		if b.Factor == 0 {
+			b.Factor = defaults.Factor
+		}
+
+		t.retryPolicy = p
+		return nil
+	}
+}
+
+// WithoutRetries disables retrying failed API calls
+func WithoutRetries() TranslatorOption {
+	return func(t *Translator) error {
+		t.retryPolicy.MaxAttempts = 1
+		return nil
+	}
+}
+
+// options returns the retry options corresponding to the policy
+func (p *RetryPolicy) options() []retry.Option {
+	backoff := p.Backoff
+
+	retryIf := p.RetryIf
+	if retryIf == nil {
+		retryIf = isRetriableHTTPError
+	}
+
+	opts := []retry.Option{
+		retry.MaxAttempts(p.MaxAttempts),
+		retry.MaxElapsedTime(p.MaxElapsedTime),
+		retry.RetryIf(retryIf),
+		retry.WithBackoff(&backoff),
+		retry.WithDelayHint(func(err error) (time.Duration, bool) {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				return apiErr.RetryAfter()
+			}
+			return 0, false
+		}),
+	}
+	if p.OnRetry != nil {
+		opts = append(opts, retry.OnRetry(p.OnRetry))
+	}
+
+	return opts
+}
+
+type noRetriesKey struct{}
+
+// withoutRetries returns a context for API calls that must not be retried
+func withoutRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetriesKey{}, true)
+}
+
+func retriesDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noRetriesKey{}).(bool)
+	return disabled
+}
+
+func isRetriableHTTPError(err error) bool {
+	switch {
+	case errors.Is(err, ErrTooManyRequests):
+		return true
+	case errors.Is(err, ErrorStatusInternalServerError):
+		return true
+	}
+	return false
+}
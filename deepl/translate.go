@@ -2,6 +2,7 @@ package deepl
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,48 +12,92 @@ import (
 type Translation struct {
 	DetectedSourceLanguage string `json:"detected_source_language"`
 	Text                   string `json:"text"`
+	// The number of billed characters, only set if requested using
+	// WithShowBilledCharacters
+	BilledCharacters int `json:"billed_characters,omitempty"`
+	// The translation model used, only set if requested using WithModelType
+	ModelTypeUsed string `json:"model_type_used,omitempty"`
+}
+
+// translateRequest holds the data of a text translation request.
+type translateRequest struct {
+	Text       []string `json:"text"`
+	TargetLang string   `json:"target_lang"`
+
+	TranslateOptions
 }
 
 // TranslateText translates the given text(s) into the specified target language.
 //
 // The total request body size must not exceed 128 KiB (128 · 1024 bytes).
 func (t *Translator) TranslateText(text []string, targetLang string, opts ...TranslateOption) ([]Translation, error) {
-	const (
-		endpoint string = "v2/translate"
-		method   string = http.MethodPost
-	)
-
-	data := struct {
-		Text       []string `json:"text"`
-		TargetLang string   `json:"target_lang"`
+	return t.TranslateTextContext(context.Background(), text, targetLang, opts...)
+}
 
-		TranslateOptions
-	}{
+// TranslateTextContext is like TranslateText but uses the given context for
+// the underlying requests.
+func (t *Translator) TranslateTextContext(ctx context.Context, text []string, targetLang string, opts ...TranslateOption) ([]Translation, error) {
+	data := translateRequest{
 		Text:       text,
 		TargetLang: targetLang,
 	}
 	if err := data.TranslateOptions.Gather(opts...); err != nil {
 		return nil, fmt.Errorf("error setting translate option: %w", err)
 	}
+	data.TranslateOptions.resolveGlossary(targetLang)
+
+	if t.strict {
+		if err := t.validateTranslateOptions(ctx, targetLang, data.TranslateOptions); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.cache != nil {
+		return t.translateTextCached(ctx, data)
+	}
+
+	return t.translateText(ctx, data)
+}
+
+// translateText sends the given text translation request
+func (t *Translator) translateText(ctx context.Context, data translateRequest) ([]Translation, error) {
+	const (
+		endpoint string = "v2/translate"
+		method   string = http.MethodPost
+	)
 
 	// Setup request
 	headers := make(http.Header)
 	headers.Set("Content-Type", "application/json")
 
-	body, err := json.Marshal(data)
+	body, err := encodeJSON(data)
 	if err != nil {
 		return nil, fmt.Errorf("error encoding request data: %w", err)
 	}
 
+	n := countCharacters(data.Text)
+	if err := t.reserveBudget(ctx, n); err != nil {
+		return nil, err
+	}
+
+	if err := t.limiter.waitCharacters(ctx, data.Text); err != nil {
+		t.budget.release(n)
+		return nil, err
+	}
+	ctx = withRequestCharacters(ctx, n)
+
 	// Send request
-	res, err := t.callAPI(method, endpoint, headers, bytes.NewReader(body))
+	res, err := t.callAPI(ctx, method, endpoint, headers, bytes.NewReader(body))
 	if err != nil {
+		t.budget.release(n)
 		return nil, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return nil, httpError(res.StatusCode)
+		t.budget.release(n)
+		return nil, httpError(endpoint, res)
 	}
+	t.budget.commit(n)
 
 	// Parse response
 	var response struct {
@@ -62,5 +107,30 @@ func (t *Translator) TranslateText(text []string, targetLang string, opts ...Tra
 		return nil, err
 	}
 
+	if t.metrics != nil {
+		billed := n
+		if data.ShowBilledCharacters != nil && *data.ShowBilledCharacters {
+			billed = 0
+			for _, tr := range response.Translations {
+				billed += tr.BilledCharacters
+			}
+		}
+		t.metrics.CharactersBilled(endpoint, billed)
+	}
+
 	return response.Translations, nil
 }
+
+// encodeJSON is like json.Marshal but does not escape HTML characters, so the
+// size of texts in request bodies equals their size computed for batching
+func encodeJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,155 @@
+package deepl_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+func TestTranslateText(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+
+	texts := []string{"Hello", "World"}
+	translations, err := translator.TranslateText(texts, "DE")
+	if err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	if len(translations) != len(texts) {
+		t.Fatalf("got %d translations, want %d", len(translations), len(texts))
+	}
+	for i, tr := range translations {
+		if want := deepltest.Translate(texts[i], "DE"); tr.Text != want {
+			t.Errorf("got translation %q, want %q", tr.Text, want)
+		}
+		if tr.DetectedSourceLanguage != deepltest.DetectedSourceLanguage {
+			t.Errorf("got detected source language %q, want %q", tr.DetectedSourceLanguage, deepltest.DetectedSourceLanguage)
+		}
+	}
+}
+
+func TestTranslateTextOptions(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+
+	translations, err := translator.TranslateText([]string{"Hello"}, "DE",
+		deepl.WithSourceLang("FR"),
+		deepl.WithShowBilledCharacters(true),
+		deepl.WithModelType("prefer_quality_optimized"),
+	)
+	if err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	tr := translations[0]
+	if tr.DetectedSourceLanguage != "FR" {
+		t.Errorf("got detected source language %q, want FR", tr.DetectedSourceLanguage)
+	}
+	if tr.BilledCharacters != 5 {
+		t.Errorf("got %d billed characters, want 5", tr.BilledCharacters)
+	}
+	if tr.ModelTypeUsed != "quality_optimized" {
+		t.Errorf("got model type %q, want quality_optimized", tr.ModelTypeUsed)
+	}
+}
+
+func TestTranslateTextUnsupportedLanguage(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+
+	_, err := translator.TranslateText([]string{"Hello"}, "EN")
+
+	var apiErr *deepl.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("got error %v, want *APIError with status 400", err)
+	}
+	if apiErr.Message == "" {
+		t.Error("got empty error message")
+	}
+}
+
+func TestTranslateTextAuthFailure(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	translator, err := deepl.NewTranslator("invalid", deepl.WithServerURL(srv.URL))
+	if err != nil {
+		t.Fatalf("failed to create translator: %v", err)
+	}
+
+	_, err = translator.TranslateText([]string{"Hello"}, "DE")
+	if !errors.Is(err, deepl.ErrAuthFailed) {
+		t.Fatalf("got error %v, want ErrAuthFailed", err)
+	}
+}
+
+func TestTranslateTextQuotaExceeded(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithCharacterLimit(10))
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+
+	if _, err := translator.TranslateText([]string{"Hello"}, "DE"); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	_, err := translator.TranslateText([]string{"Hello, World!"}, "DE")
+
+	var apiErr *deepl.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != deepl.StatusQuotaExceeded {
+		t.Fatalf("got error %v, want *APIError with status 456", err)
+	}
+	if !errors.Is(err, deepl.ErrQuotaExceeded) {
+		t.Errorf("error %v does not match ErrQuotaExceeded", err)
+	}
+	if usage := srv.Usage(); usage.CharacterCount != 5 {
+		t.Errorf("got character count %d, want 5", usage.CharacterCount)
+	}
+}
+
+func TestTranslateTextRateLimit(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	client := &recordingClient{client: srv.Client()}
+	translator := newTestTranslator(t, srv, deepl.WithHTTPClient(client), deepl.WithRateLimit(deepl.RateLimit{
+		RequestsPerSecond:   50,
+		RequestBurst:        1,
+		CharactersPerMinute: 600,
+	}))
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := translator.TranslateText([]string{"Hello"}, "DE"); err != nil {
+			t.Fatalf("TranslateText: %v", err)
+		}
+	}
+	// every request after the first one waits 20ms
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("sent 4 requests in %v, want at least 60ms", elapsed)
+	}
+
+	// the character budget of a minute is used up
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := translator.TranslateTextContext(ctx, []string{strings.Repeat("x", 600)}, "DE")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := len(client.attempts("/v2/translate")); n != 4 {
+		t.Errorf("got %d requests, want 4", n)
+	}
+}
@@ -1,11 +1,13 @@
 package deepl
 
 import (
-	"errors"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/cluttrdev/deepl-go/internal/retry"
@@ -17,9 +19,24 @@ const (
 )
 
 type Translator struct {
-	client    HTTPClient
-	serverURL string
-	authKey   string
+	client      HTTPClient
+	middlewares []Middleware
+	serverURL   string
+	authKey     string
+
+	retryPolicy    RetryPolicy
+	maxConcurrency int
+	limiter        *rateLimiter
+	budget         *budgetGuard
+	documents      documentTracker
+
+	cache       Cache
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
+
+	metadata metadataCache
+	strict   bool
+	metrics  Metrics
 }
 
 // TranslatorOption is a functional option for configuring the Translator
@@ -58,11 +75,23 @@ func NewTranslator(authKey string, opts ...TranslatorOption) (*Translator, error
 		},
 		serverURL: serverURL,
 		authKey:   authKey,
+
+		retryPolicy:    DefaultRetryPolicy(),
+		maxConcurrency: defaultMaxConcurrency,
+
+		metadata: metadataCache{
+			ttl: defaultMetadataTTL,
+		},
 	}
 
 	if err := t.applyOptions(opts...); err != nil {
 		return nil, err
 	}
+	middlewares := t.middlewares
+	if t.metrics != nil {
+		middlewares = append(middlewares[:len(middlewares):len(middlewares)], metricsMiddleware(t.metrics))
+	}
+	t.client = chainMiddleware(t.client, middlewares)
 
 	return t, nil
 }
@@ -79,55 +108,126 @@ func (t *Translator) applyOptions(opts ...TranslatorOption) error {
 	return nil
 }
 
+// bodyFunc returns a fresh request body for every attempt of an API call
+type bodyFunc func() (io.Reader, error)
+
 // callAPI calls the supplied API endpoint with the provided parameters and returns the response
-func (t *Translator) callAPI(method string, endpoint string, headers http.Header, body io.Reader) (*http.Response, error) {
+func (t *Translator) callAPI(ctx context.Context, method string, endpoint string, headers http.Header, body io.Reader) (*http.Response, error) {
+	getBody, err := replayableBody(body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %w", err)
+	}
+
+	return t.callAPIWithBody(ctx, method, endpoint, headers, getBody)
+}
+
+// callAPIWithBody is like callAPI but obtains the request body of each
+// attempt from the supplied function, which may be nil.
+func (t *Translator) callAPIWithBody(ctx context.Context, method string, endpoint string, headers http.Header, getBody bodyFunc) (*http.Response, error) {
 	url := fmt.Sprintf("%s/%s", t.serverURL, endpoint)
 
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+	info := RequestInfo{
+		Endpoint:   endpoint,
+		Route:      endpointRoute(endpoint),
+		Characters: requestCharacters(ctx),
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", t.authKey))
-	for k, vs := range headers {
-		for _, v := range vs {
-			req.Header.Set(k, v)
+	newRequest := func() (*http.Request, error) {
+		var body io.Reader
+		if getBody != nil {
+			b, err := getBody()
+			if err != nil {
+				return nil, fmt.Errorf("error creating request body: %w", err)
+			}
+			body = b
+		}
+
+		info.Attempt++
+		reqCtx := context.WithValue(ctx, requestInfoKey{}, info)
+
+		req, err := http.NewRequestWithContext(reqCtx, method, url, body)
+		if err != nil {
+			if c, ok := body.(io.Closer); ok {
+				c.Close()
+			}
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+
+		req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", t.authKey))
+		for k, vs := range headers {
+			for _, v := range vs {
+				req.Header.Set(k, v)
+			}
 		}
+
+		return req, nil
+	}
+
+	opts := append(t.retryPolicy.options(), retry.WithContext(ctx))
+	if retriesDisabled(ctx) {
+		opts = append(opts, retry.MaxAttempts(1))
+	}
+	if t.metrics != nil {
+		onRetry := t.retryPolicy.OnRetry
+		opts = append(opts, retry.OnRetry(func(attempt int, delay time.Duration, err error) {
+			t.metrics.RequestRetried(info.Route, attempt)
+			if onRetry != nil {
+				onRetry(attempt, delay, err)
+			}
+		}))
 	}
 
 	res, err := retry.DoWithData(
 		func() (*http.Response, error) {
+			if err := t.limiter.waitRequest(ctx); err != nil {
+				return nil, err
+			}
+
+			req, err := newRequest()
+			if err != nil {
+				return nil, err
+			}
+
 			res, err := t.client.Do(req)
 			if err != nil {
-				return res, err
-			} else if err := retriableHTTPError(res.StatusCode); err != nil {
-				return res, err
+				return nil, err
+			} else if isRetriableStatusCode(res.StatusCode) {
+				return nil, httpError(endpoint, res)
 			}
 			return res, nil
 		},
-		retry.RetryIf(func(err error) bool {
-			return isRetriableHTTPError(err)
-		}),
-		retry.MaxAttempts(5),
-		retry.WithBackoff(&retry.Backoff{
-			InitialDelay: 1 * time.Second,
-			MaxDelay:     120 * time.Second,
-			Factor:       1.6,
-			Jitter:       0.23,
-		}),
+		opts...,
 	)
 
 	return res, err
 }
 
-func isRetriableHTTPError(err error) bool {
-	switch {
-	case errors.Is(err, ErrorStatusTooManyRequests):
-		return true
-	case errors.Is(err, ErrorStatusInternalServerError):
-		return true
+// replayableBody returns a function that yields the contents of the given
+// reader on every call. Seekable readers are rewound, others are buffered,
+// including readers that implement io.Seeker but cannot seek, e.g. pipes.
+func replayableBody(body io.Reader) (bodyFunc, error) {
+	if body == nil {
+		return nil, nil
+	}
+
+	if rs, ok := body.(io.ReadSeeker); ok {
+		if offset, err := rs.Seek(0, io.SeekCurrent); err == nil {
+			return func() (io.Reader, error) {
+				if _, err := rs.Seek(offset, io.SeekStart); err != nil {
+					return nil, err
+				}
+				return rs, nil
+			}, nil
+		}
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
 	}
-	return false
+	return func() (io.Reader, error) {
+		return bytes.NewReader(data), nil
+	}, nil
 }
 
 // isFreeAccountAuthKey determines whether the supplied auth key belongs to a Free account
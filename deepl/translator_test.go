@@ -0,0 +1,379 @@
+package deepl_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+// newTestTranslator returns a translator using the given server
+func newTestTranslator(t *testing.T, srv *deepltest.Server, opts ...deepl.TranslatorOption) *deepl.Translator {
+	t.Helper()
+
+	opts = append([]deepl.TranslatorOption{deepl.WithServerURL(srv.URL)}, opts...)
+	translator, err := deepl.NewTranslator(srv.AuthKey, opts...)
+	if err != nil {
+		t.Fatalf("failed to create translator: %v", err)
+	}
+	return translator
+}
+
+// fastRetries retries failed calls without noticeable delay
+func fastRetries() deepl.TranslatorOption {
+	return deepl.WithRetryPolicy(deepl.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff: deepl.Backoff{
+			InitialDelay: time.Millisecond,
+			MaxDelay:     5 * time.Second,
+			Factor:       1,
+		},
+	})
+}
+
+// recordingClient records the request body of every attempt and tracks
+// whether the response bodies are closed
+type recordingClient struct {
+	client deepl.HTTPClient
+
+	mu        sync.Mutex
+	requests  []recordedAttempt
+	responses []*trackedBody
+}
+
+type recordedAttempt struct {
+	path string
+	body []byte
+}
+
+type trackedBody struct {
+	io.ReadCloser
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (b *trackedBody) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	return b.ReadCloser.Close()
+}
+
+func (c *recordingClient) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+	}
+
+	c.mu.Lock()
+	c.requests = append(c.requests, recordedAttempt{path: req.URL.Path, body: body})
+	c.mu.Unlock()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := &trackedBody{ReadCloser: res.Body}
+	res.Body = tracked
+
+	c.mu.Lock()
+	c.responses = append(c.responses, tracked)
+	c.mu.Unlock()
+
+	return res, nil
+}
+
+// attempts returns the request bodies sent to the given path
+func (c *recordingClient) attempts(path string) [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var bodies [][]byte
+	for _, r := range c.requests {
+		if r.path == path {
+			bodies = append(bodies, r.body)
+		}
+	}
+	return bodies
+}
+
+// unclosed returns the number of response bodies that were not closed
+func (c *recordingClient) unclosed() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, b := range c.responses {
+		b.mu.Lock()
+		if !b.closed {
+			n++
+		}
+		b.mu.Unlock()
+	}
+	return n
+}
+
+func checkResentBodies(t *testing.T, bodies [][]byte) {
+	t.Helper()
+
+	if len(bodies) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(bodies))
+	}
+	if len(bodies[0]) == 0 {
+		t.Fatal("first attempt has an empty body")
+	}
+	if !bytes.Equal(bodies[0], bodies[1]) {
+		t.Errorf("retried body differs:\nfirst: %q\nretry: %q", bodies[0], bodies[1])
+	}
+}
+
+func TestRetryResendsTranslateBody(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:     deepltest.FaultServiceUnavailable,
+		Endpoint: "v2/translate",
+		Nth:      1,
+	}))
+	defer srv.Close()
+
+	client := &recordingClient{client: srv.Client()}
+	translator := newTestTranslator(t, srv, deepl.WithHTTPClient(client), fastRetries())
+
+	translations, err := translator.TranslateText([]string{"Hello"}, "DE")
+	if err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	if want := deepltest.Translate("Hello", "DE"); translations[0].Text != want {
+		t.Errorf("got translation %q, want %q", translations[0].Text, want)
+	}
+
+	checkResentBodies(t, client.attempts("/v2/translate"))
+	if n := client.unclosed(); n > 0 {
+		t.Errorf("%d response bodies were not closed", n)
+	}
+}
+
+func TestRetryResendsDocumentBody(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:     deepltest.FaultServiceUnavailable,
+		Endpoint: "v2/document",
+		Nth:      1,
+	}))
+	defer srv.Close()
+
+	client := &recordingClient{client: srv.Client()}
+	translator := newTestTranslator(t, srv, deepl.WithHTTPClient(client), fastRetries())
+
+	// a reader that is neither seekable nor replayable by itself
+	r := io.MultiReader(strings.NewReader("Hello, World!"))
+
+	doc, err := translator.TranslateDocumentUploadReader(context.Background(), r, "hello.txt", "", "DE")
+	if err != nil {
+		t.Fatalf("TranslateDocumentUploadReader: %v", err)
+	}
+	if doc.DocumentId == "" {
+		t.Error("got empty document id")
+	}
+
+	bodies := client.attempts("/v2/document")
+	checkResentBodies(t, bodies)
+	if !bytes.Contains(bodies[1], []byte("Hello, World!")) {
+		t.Errorf("retried body does not contain the document: %q", bodies[1])
+	}
+	if n := client.unclosed(); n > 0 {
+		t.Errorf("%d response bodies were not closed", n)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:       deepltest.FaultTooManyRequests,
+		Endpoint:   "v2/translate",
+		Nth:        1,
+		RetryAfter: time.Second,
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	translator := newTestTranslator(t, srv, deepl.WithRetryPolicy(deepl.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff: deepl.Backoff{
+			InitialDelay: time.Millisecond,
+			MaxDelay:     5 * time.Second,
+			Factor:       1,
+		},
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			delays = append(delays, delay)
+		},
+	}))
+
+	start := time.Now()
+	if _, err := translator.TranslateText([]string{"Hello"}, "DE"); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+
+	if len(delays) != 1 || delays[0] != time.Second {
+		t.Errorf("got retry delays %v, want [1s]", delays)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least 1s", elapsed)
+	}
+}
+
+func TestRetryAfterExceedingMaxDelay(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:       deepltest.FaultTooManyRequests,
+		Endpoint:   "v2/translate",
+		Nth:        1,
+		RetryAfter: 24 * time.Hour,
+	}))
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv, fastRetries())
+
+	start := time.Now()
+	_, err := translator.TranslateText([]string{"Hello"}, "DE")
+
+	var apiErr *deepl.APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, deepl.ErrTooManyRequests) {
+		t.Fatalf("got error %v, want *APIError matching ErrTooManyRequests", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("gave up after %v, want immediately", elapsed)
+	}
+}
+
+func TestUploadDocumentFromPipe(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	defer r.Close()
+	go func() {
+		_, _ = io.WriteString(w, "Hello, World!")
+		w.Close()
+	}()
+
+	var out bytes.Buffer
+	if _, err := translator.TranslateDocument(context.Background(), r, "hello.txt", &out, "DE"); err != nil {
+		t.Fatalf("TranslateDocument: %v", err)
+	}
+	if want := deepltest.Translate("Hello, World!", "DE"); out.String() != want {
+		t.Errorf("got document %q, want %q", out.String(), want)
+	}
+}
+
+func TestRetryPolicyPartialBackoff(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithFaults(deepltest.Fault{
+		Kind:     deepltest.FaultServiceUnavailable,
+		Endpoint: "v2/translate",
+		Percent:  100,
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	translator := newTestTranslator(t, srv, deepl.WithRetryPolicy(deepl.RetryPolicy{
+		MaxAttempts: 3,
+		// neither the initial delay, the factor nor the maximum delay is set
+		Backoff: deepl.Backoff{},
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			delays = append(delays, delay)
+		},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	_, err := translator.TranslateTextContext(ctx, []string{"Hello"}, "DE")
+	if err == nil {
+		t.Fatal("got no error")
+	}
+
+	// the default initial delay is used
+	if len(delays) != 1 || delays[0] < 500*time.Millisecond {
+		t.Errorf("got retry delays %v, want one default delay", delays)
+	}
+}
+
+func TestRetryPolicyInvalidBackoff(t *testing.T) {
+	_, err := deepl.NewTranslator("key", deepl.WithRetryPolicy(deepl.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     deepl.Backoff{Factor: -1},
+	}))
+	if err == nil {
+		t.Error("got no error for negative backoff factor")
+	}
+}
+
+// eofReader records whether it has been read to the end
+type eofReader struct {
+	r   io.Reader
+	eof atomic.Bool
+}
+
+func (r *eofReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		r.eof.Store(true)
+	}
+	return n, err
+}
+
+func TestUploadDocumentStreamsWithoutRetries(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		opts []deepl.TranslatorOption
+		// whether the document is read before the request is sent
+		buffered bool
+	}{
+		{name: "with retries", buffered: true},
+		{name: "without retries", opts: []deepl.TranslatorOption{deepl.WithoutRetries()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &eofReader{r: strings.NewReader("Hello, World!")}
+
+			var buffered bool
+			client := deepl.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+				buffered = r.eof.Load()
+				return srv.Client().Do(req)
+			})
+			translator := newTestTranslator(t, srv, append(tt.opts, deepl.WithHTTPClient(client))...)
+
+			if _, err := translator.TranslateDocumentUploadReader(context.Background(), r, "hello.txt", "", "DE"); err != nil {
+				t.Fatalf("TranslateDocumentUploadReader: %v", err)
+			}
+			if buffered != tt.buffered {
+				t.Errorf("got buffered %t, want %t", buffered, tt.buffered)
+			}
+		})
+	}
+}
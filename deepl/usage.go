@@ -1,6 +1,7 @@
 package deepl
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 )
@@ -15,18 +16,22 @@ type Usage struct {
 }
 
 func (t *Translator) GetUsage() (*Usage, error) {
+	return t.GetUsageContext(context.Background())
+}
+
+func (t *Translator) GetUsageContext(ctx context.Context) (*Usage, error) {
 	const (
 		endpoint string = "v2/usage"
 		method   string = http.MethodGet
 	)
 
-	res, err := t.callAPI(method, endpoint, nil, nil)
+	res, err := t.callAPI(ctx, method, endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return nil, httpError(res.StatusCode)
+		return nil, httpError(endpoint, res)
 	}
 
 	var usage Usage
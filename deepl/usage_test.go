@@ -0,0 +1,31 @@
+package deepl_test
+
+import (
+	"testing"
+
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+func TestGetUsage(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithCharacterLimit(1000))
+	defer srv.Close()
+
+	translator := newTestTranslator(t, srv)
+
+	if _, err := translator.TranslateText([]string{"Hello"}, "DE"); err != nil {
+		t.Fatalf("TranslateText: %v", err)
+	}
+	srv.SetCharacterCount(srv.Usage().CharacterCount + 100)
+
+	usage, err := translator.GetUsage()
+	if err != nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+
+	if usage.CharacterCount != 105 {
+		t.Errorf("got character count %d, want 105", usage.CharacterCount)
+	}
+	if usage.CharacterLimit != 1000 {
+		t.Errorf("got character limit %d, want 1000", usage.CharacterLimit)
+	}
+}
@@ -0,0 +1,142 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidTranslateOptions is returned if translate options are rejected by
+// the pre-flight validation.
+var ErrInvalidTranslateOptions = errors.New("invalid translate options")
+
+// WithStrictValidation enables validating translate options against the
+// capabilities of the target language before text translation requests are
+// sent, see ValidateTranslateOptions
+func WithStrictValidation() TranslatorOption {
+	return func(t *Translator) error {
+		t.strict = true
+		return nil
+	}
+}
+
+// ValidateTranslateOptions checks that the given options can be used to
+// translate text into the target language. It checks that
+//   - the source and target language are supported
+//   - the target language supports the requested formality
+//   - a glossary is used with a source language and matches the language pair
+//   - XML specific options are only used with XML tag handling
+//
+// All problems found are joined into the returned error, which matches
+// ErrInvalidTranslateOptions.
+func (t *Translator) ValidateTranslateOptions(ctx context.Context, targetLang string, opts ...TranslateOption) error {
+	var options TranslateOptions
+	if err := options.Gather(opts...); err != nil {
+		return fmt.Errorf("error setting translate option: %w", err)
+	}
+	options.resolveGlossary(targetLang)
+
+	return t.validateTranslateOptions(ctx, targetLang, options)
+}
+
+func (t *Translator) validateTranslateOptions(ctx context.Context, targetLang string, options TranslateOptions) error {
+	var errs []error
+	invalid := func(format string, a ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidTranslateOptions, fmt.Sprintf(format, a...)))
+	}
+
+	targetSupported := hasLanguage(t.languagesWithFallback(ctx, "target"), targetLang)
+	if !targetSupported {
+		invalid("unsupported target language: %s", targetLang)
+	}
+	if options.SourceLang != nil && !hasLanguage(t.languagesWithFallback(ctx, "source"), *options.SourceLang) {
+		invalid("unsupported source language: %s", *options.SourceLang)
+	}
+
+	if options.Formality != nil && targetSupported {
+		switch *options.Formality {
+		case "more", "less":
+			if !t.SupportsFormalityContext(ctx, targetLang) {
+				invalid("formality `%s` is not supported for target language %s", *options.Formality, targetLang)
+			}
+		}
+	}
+
+	if options.GlossaryID != nil {
+		if options.SourceLang == nil {
+			invalid("glossary requires `source_lang` to be set")
+		} else if err := t.validateGlossary(ctx, *options.GlossaryID, *options.SourceLang, targetLang); err != nil {
+			if !errors.Is(err, ErrInvalidTranslateOptions) {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	if options.TagHandling == nil || *options.TagHandling != "xml" {
+		if options.OutlineDetection != nil {
+			invalid("`outline_detection` requires `tag_handling=xml`")
+		}
+		if options.SplittingTags != nil {
+			invalid("`splitting_tags` requires `tag_handling=xml`")
+		}
+		if options.NonSplittingTags != nil {
+			invalid("`non_splitting_tags` requires `tag_handling=xml`")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateGlossary checks that the glossary matches the given language pair
+func (t *Translator) validateGlossary(ctx context.Context, glossaryID string, sourceLang string, targetLang string) error {
+	source, target := baseLangCodeOf(sourceLang), baseLangCodeOf(targetLang)
+
+	if !t.GlossaryPairSupportedContext(ctx, source, target) {
+		return fmt.Errorf("%w: glossaries are not supported for %s to %s", ErrInvalidTranslateOptions, source, target)
+	}
+
+	pair, err := t.glossaryLanguagePair(ctx, glossaryID)
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("%w: glossary not found: %s", ErrInvalidTranslateOptions, glossaryID)
+	} else if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(pair.SourceLang, source) || !strings.EqualFold(pair.TargetLang, target) {
+		return fmt.Errorf("%w: glossary %s is for %s to %s, not %s to %s",
+			ErrInvalidTranslateOptions, glossaryID, pair.SourceLang, pair.TargetLang, source, target)
+	}
+	return nil
+}
+
+// glossaryLanguagePair returns the language pair of the given glossary.
+// Glossaries cannot be modified, so the pairs are cached indefinitely.
+func (t *Translator) glossaryLanguagePair(ctx context.Context, glossaryID string) (LanguagePair, error) {
+	if pair, ok := t.metadata.getGlossaryPair(glossaryID); ok {
+		return pair, nil
+	}
+
+	g, err := t.GetGlossaryContext(ctx, glossaryID)
+	if err != nil {
+		return LanguagePair{}, err
+	}
+
+	pair := LanguagePair{
+		SourceLang: g.SourceLang,
+		TargetLang: g.TargetLang,
+	}
+	t.metadata.setGlossaryPair(glossaryID, pair)
+
+	return pair, nil
+}
+
+func hasLanguage(languages []Language, code string) bool {
+	for _, lang := range languages {
+		if strings.EqualFold(lang.Code, code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package deepl_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cluttrdev/deepl-go/deepl"
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+)
+
+func TestStrictValidation(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	client := &recordingClient{client: srv.Client()}
+	translator := newTestTranslator(t, srv, deepl.WithHTTPClient(client), deepl.WithStrictValidation())
+
+	glossary, err := translator.CreateGlossary("greetings", "EN", "DE", []deepl.GlossaryEntry{{Source: "Hello", Target: "Hallo"}})
+	if err != nil {
+		t.Fatalf("CreateGlossary: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		opts   []deepl.TranslateOption
+		// the number of problems reported
+		problems int
+	}{
+		{
+			name:     "unsupported target",
+			target:   "XX",
+			problems: 1,
+		},
+		{
+			name:     "unsupported source",
+			target:   "DE",
+			opts:     []deepl.TranslateOption{deepl.WithSourceLang("XX")},
+			problems: 1,
+		},
+		{
+			name:     "unsupported formality",
+			target:   "EN-GB",
+			opts:     []deepl.TranslateOption{deepl.WithFormality("more")},
+			problems: 1,
+		},
+		{
+			name:     "glossary without source",
+			target:   "DE",
+			opts:     []deepl.TranslateOption{deepl.WithGlossaryID(glossary.GlossaryId)},
+			problems: 1,
+		},
+		{
+			name:     "unsupported glossary pair",
+			target:   "ET",
+			opts:     []deepl.TranslateOption{deepl.WithSourceLang("EN"), deepl.WithGlossaryID(glossary.GlossaryId)},
+			problems: 1,
+		},
+		{
+			name:     "mismatching glossary",
+			target:   "FR",
+			opts:     []deepl.TranslateOption{deepl.WithSourceLang("EN"), deepl.WithGlossaryID(glossary.GlossaryId)},
+			problems: 1,
+		},
+		{
+			name:   "xml options without xml tag handling",
+			target: "DE",
+			opts: []deepl.TranslateOption{
+				deepl.WithOutlineDetection(false),
+				deepl.WithSplittingTags([]string{"p"}),
+				deepl.WithNonSplittingTags([]string{"b"}),
+			},
+			problems: 3,
+		},
+		{
+			name:     "multiple problems",
+			target:   "EN-US",
+			opts:     []deepl.TranslateOption{deepl.WithSourceLang("XX"), deepl.WithFormality("less"), deepl.WithTagHandling("html"), deepl.WithOutlineDetection(false)},
+			problems: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := translator.TranslateTextContext(context.Background(), []string{"Hello"}, tt.target, tt.opts...)
+			if !errors.Is(err, deepl.ErrInvalidTranslateOptions) {
+				t.Fatalf("got error %v, want ErrInvalidTranslateOptions", err)
+			}
+
+			var joined interface{ Unwrap() []error }
+			if !errors.As(err, &joined) {
+				t.Fatalf("got error %v, want joined errors", err)
+			}
+			if n := len(joined.Unwrap()); n != tt.problems {
+				t.Errorf("got %d problems, want %d: %v", n, tt.problems, err)
+			}
+		})
+	}
+
+	// no invalid translation is sent
+	if n := len(client.attempts("/v2/translate")); n != 0 {
+		t.Errorf("got %d translate requests, want none", n)
+	}
+
+	// valid options pass
+	opts := []deepl.TranslateOption{
+		deepl.WithSourceLang("EN"),
+		deepl.WithFormality("more"),
+		deepl.WithGlossaryID(glossary.GlossaryId),
+		deepl.WithTagHandling("xml"),
+		deepl.WithOutlineDetection(false),
+	}
+	if _, err := translator.TranslateText([]string{"Hello"}, "DE", opts...); err != nil {
+		t.Errorf("TranslateText: %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
 	"github.com/cluttrdev/deepl-go/deepl"
@@ -47,7 +48,7 @@ func NewDocumentUploadCmd(stdout io.Writer, stderr io.Writer) *command.Command {
 		Name:       "upload",
 		ShortHelp:  "Upload documents for translation",
 		ShortUsage: "deepl document upload [option]... --target-lang=LANG FILE...",
-		LongHelp:   "",
+		LongHelp:   "If FILE is `-`, the document is read from standard input and `--filename` is required.",
 		Flags:      cfg.flags,
 		Exec:       cfg.Exec,
 	}
@@ -58,21 +59,27 @@ type DocumentUploadCmdConfig struct {
 
 	flags *flag.FlagSet
 
-	targetLang string
-	sourceLang string
-	formality  string
-	glossaryID string
+	targetLang   string
+	sourceLang   string
+	formality    string
+	glossaryID   string
+	outputFormat string
+	filename     string
 }
 
 func (c *DocumentUploadCmdConfig) RegisterFlags(fs *flag.FlagSet) {
 	c.RootCmdConfig.RegisterFlags(fs)
+	c.RootCmdConfig.registerBudgetFlags(fs)
 
 	fs.StringVar(&c.targetLang, "target-lang", "", "the language into which the text should be translated (required)")
 	fs.StringVar(&c.targetLang, "to", "", "alias option for `--target-lang`")
 	fs.StringVar(&c.sourceLang, "source-lang", "", "the language to be translated")
 	fs.StringVar(&c.sourceLang, "from", "", "alias option for `--source-lang`")
 	fs.StringVar(&c.formality, "formality", "default", "whether the engine should lean towards formal or informal language")
-	fs.StringVar(&c.glossaryID, "glossary_id", "", "the glossary to use for the translation")
+	fs.StringVar(&c.glossaryID, "glossary-id", "", "the glossary to use for the translation")
+	fs.StringVar(&c.glossaryID, "glossary_id", "", "deprecated alias option for `--glossary-id`")
+	fs.StringVar(&c.outputFormat, "output-format", "", "the file format of the translated document, e.g. `docx` for PDF documents")
+	fs.StringVar(&c.filename, "filename", "", "the filename determining the document format, required when reading from standard input")
 }
 
 func (c *DocumentUploadCmdConfig) Exec(ctx context.Context, args []string) error {
@@ -86,20 +93,27 @@ func (c *DocumentUploadCmdConfig) Exec(ctx context.Context, args []string) error
 		return flag.ErrHelp
 	}
 
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
 
-	opts := []deepl.TranslateOption{}
+	opts := []deepl.DocumentOption{}
 	c.flags.Visit(func(f *flag.Flag) {
 		switch f.Name {
-		case "source-lang":
+		case "source-lang", "from":
 			opts = append(opts, deepl.WithSourceLang(c.sourceLang))
 		case "formality":
 			opts = append(opts, deepl.WithFormality(c.formality))
-		case "glossary-id":
+		case "glossary-id", "glossary_id":
+			if f.Name == "glossary_id" {
+				fmt.Fprintln(c.stderr, "Warning: document upload: `--glossary_id` is deprecated, use `--glossary-id` instead")
+			}
 			opts = append(opts, deepl.WithGlossaryID(c.glossaryID))
+		case "output-format":
+			opts = append(opts, deepl.WithOutputFormat(c.outputFormat))
+		case "filename":
+			opts = append(opts, deepl.WithFilename(c.filename))
 		}
 	})
 
@@ -120,7 +134,19 @@ func (c *DocumentUploadCmdConfig) Exec(ctx context.Context, args []string) error
 	}()
 
 	for _, path := range args {
-		di, err := t.TranslateDocumentUpload(path, c.targetLang, opts...)
+		var (
+			di  *deepl.DocumentInfo
+			err error
+		)
+		if path == "-" {
+			if c.filename == "" {
+				fmt.Fprintln(c.stderr, "Error: document upload: `--filename` is required when reading from standard input")
+				return flag.ErrHelp
+			}
+			di, err = t.TranslateDocumentUploadReader(ctx, os.Stdin, c.filename, "", c.targetLang, opts...)
+		} else {
+			di, err = t.TranslateDocumentUploadContext(ctx, path, c.targetLang, opts...)
+		}
 		if err != nil {
 			return err
 		}
@@ -173,7 +199,7 @@ func (c *DocumentStatusCmdConfig) Exec(ctx context.Context, args []string) error
 		return flag.ErrHelp
 	}
 
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
@@ -201,7 +227,7 @@ func (c *DocumentStatusCmdConfig) Exec(ctx context.Context, args []string) error
 	}()
 
 	for _, di := range dis {
-		ds, err := t.TranslateDocumentStatus(di.DocumentId, di.DocumentKey)
+		ds, err := t.TranslateDocumentStatusContext(ctx, di.DocumentId, di.DocumentKey)
 		if err != nil {
 			return err
 		}
@@ -254,7 +280,7 @@ func (c *DocumentDownloadCmdConfig) Exec(ctx context.Context, args []string) err
 		return flag.ErrHelp
 	}
 
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
@@ -268,7 +294,7 @@ func (c *DocumentDownloadCmdConfig) Exec(ctx context.Context, args []string) err
 		DocumentKey: info[1],
 	}
 
-	pr, err := t.TranslateDocumentDownload(di.DocumentId, di.DocumentKey)
+	pr, err := t.TranslateDocumentDownloadContext(ctx, di.DocumentId, di.DocumentKey)
 	if err != nil {
 		return nil
 	}
@@ -95,12 +95,12 @@ func (c *GlossariesLanguagePairsCmdConfig) Exec(ctx context.Context, args []stri
 		return flag.ErrHelp
 	}
 
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
 
-	lps, err := t.GetGlossaryLanguagePairs()
+	lps, err := t.GetGlossaryLanguagePairsContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -179,12 +179,12 @@ func (c *GlossariesCreateCmdConfig) Exec(ctx context.Context, args []string) err
 		entries = append(entries, deepl.GlossaryEntry{Source: pair[0], Target: pair[1]})
 	}
 
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
 
-	g, err := t.CreateGlossary(c.name, c.sourceLang, c.targetLang, entries)
+	g, err := t.CreateGlossaryContext(ctx, c.name, c.sourceLang, c.targetLang, entries)
 	if err != nil {
 		return err
 	}
@@ -238,12 +238,12 @@ func (c *GlossariesListCmdConfig) Exec(ctx context.Context, args []string) error
 		return flag.ErrHelp
 	}
 
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
 
-	gs, err := t.ListGlossaries()
+	gs, err := t.ListGlossariesContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -297,7 +297,7 @@ func (c *GlossariesInfoCmdConfig) Exec(ctx context.Context, args []string) error
 		return flag.ErrHelp
 	}
 
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
@@ -313,7 +313,7 @@ func (c *GlossariesInfoCmdConfig) Exec(ctx context.Context, args []string) error
 	}()
 
 	for _, gid := range args {
-		g, err := t.GetGlossary(gid)
+		g, err := t.GetGlossaryContext(ctx, gid)
 		if err != nil {
 			return err
 		}
@@ -370,14 +370,14 @@ func (c *GlossariesEntriesCmdConfig) Exec(ctx context.Context, args []string) er
 		return flag.ErrHelp
 	}
 
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
 
 	gid := args[0]
 
-	ges, err := t.GetGlossaryEntries(gid)
+	ges, err := t.GetGlossaryEntriesContext(ctx, gid)
 	if err != nil {
 		return err
 	}
@@ -439,7 +439,7 @@ func (c *GlossariesDeleteCmdConfig) Exec(ctx context.Context, args []string) err
 		return flag.ErrHelp
 	}
 
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
@@ -451,7 +451,7 @@ func (c *GlossariesDeleteCmdConfig) Exec(ctx context.Context, args []string) err
 	}()
 
 	for _, gid := range args {
-		err := t.DeleteGlossary(gid)
+		err := t.DeleteGlossaryContext(ctx, gid)
 		if err != nil {
 			return err
 		}
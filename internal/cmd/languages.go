@@ -54,7 +54,7 @@ func (c *LanguagesCmdConfig) RegisterFlags(fs *flag.FlagSet) {
 }
 
 func (c *LanguagesCmdConfig) Exec(ctx context.Context, args []string) error {
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
@@ -69,7 +69,7 @@ func (c *LanguagesCmdConfig) Exec(ctx context.Context, args []string) error {
 		c.langType = "target"
 	}
 
-	ls, err := t.GetLanguages(c.langType)
+	ls, err := t.GetLanguagesContext(ctx, c.langType)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/cluttrdev/deepl-go/deepl/deepltest"
+	"github.com/cluttrdev/deepl-go/internal/command"
+)
+
+type MockServerCmdConfig struct {
+	RootCmdConfig
+
+	addr           string
+	characterLimit int
+	faults         string
+}
+
+func (c *MockServerCmdConfig) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.authKey, "auth-key", deepltest.DefaultAuthKey, "the authentication key accepted by the server.")
+	fs.StringVar(&c.addr, "addr", "127.0.0.1:8080", "the address to listen on.")
+	fs.IntVar(&c.characterLimit, "character-limit", deepltest.DefaultCharacterLimit, "the number of characters that can be translated, 0 means no limit.")
+	fs.StringVar(&c.faults, "faults", "", "a comma-separated list of faults to inject, e.g. `translate:429=2s@1x3,usage:slow=500ms@50%`.")
+}
+
+func (c *MockServerCmdConfig) Exec(ctx context.Context, args []string) error {
+	faults, err := deepltest.ParseFaults(c.faults)
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", c.addr)
+	if err != nil {
+		return err
+	}
+
+	srv := deepltest.NewUnstartedServer(
+		deepltest.WithAuthKey(c.authKey),
+		deepltest.WithCharacterLimit(c.characterLimit),
+		deepltest.WithFaults(faults...),
+	)
+	srv.Listener.Close()
+	srv.Listener = l
+	srv.Start()
+	defer srv.Close()
+
+	fmt.Fprintf(c.stdout, "Serving mock DeepL API on %s\n", srv.URL)
+
+	<-ctx.Done()
+	return nil
+}
+
+func NewMockServerCmd(stdout io.Writer, stderr io.Writer) *command.Command {
+	cfg := MockServerCmdConfig{
+		RootCmdConfig: RootCmdConfig{
+			stdout: stdout,
+			stderr: stderr,
+		},
+	}
+
+	fs := flag.NewFlagSet("mock-server", flag.ContinueOnError)
+
+	cfg.RegisterFlags(fs)
+
+	return &command.Command{
+		Name:       "mock-server",
+		ShortHelp:  "Serve a fake DeepL API for testing",
+		ShortUsage: "deepl mock-server [option]...",
+		LongHelp: `Serve a fake DeepL API until interrupted.
+
+Faults are given as [ENDPOINT:]KIND[=DURATION][@TRIGGER], where KIND is one of
+429, 456, 503, slow, truncate or malformed, and TRIGGER is N (the Nth call),
+NxM (M calls starting with the Nth) or P% (a percentage of calls).`,
+		Flags: fs,
+		Exec:  cfg.Exec,
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"os/signal"
 
 	"github.com/cluttrdev/deepl-go/deepl"
 
@@ -35,6 +37,9 @@ type RootCmdConfig struct {
 	serverURL string
 
 	verbosity int
+
+	// registered by the commands that translate, see registerBudgetFlags
+	maxCharacters int
 }
 
 func NewRootCmd(stdout io.Writer, stderr io.Writer) *command.Command {
@@ -68,10 +73,22 @@ func (c *RootCmdConfig) RegisterFlags(fs *flag.FlagSet) {
 	// fs.Var(&c.output, "o", "Write to file instead of stdout.")
 }
 
+// registerBudgetFlags registers the flags limiting the characters used by the
+// account
+func (c *RootCmdConfig) registerBudgetFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.maxCharacters, "max-characters", 0, "refuse to translate if the character count of the account would exceed this number")
+}
+
 func (c *RootCmdConfig) Exec(ctx context.Context, args []string) error {
 	return flag.ErrHelp
 }
 
+// newClient creates the API client used by the commands, it may be replaced to
+// plug in an alternative backend.
+var newClient = func(cfg RootCmdConfig) (deepl.Client, error) {
+	return newTranslator(cfg)
+}
+
 func newTranslator(cfg RootCmdConfig) (*deepl.Translator, error) {
 	opts := []deepl.TranslatorOption{}
 
@@ -79,9 +96,34 @@ func newTranslator(cfg RootCmdConfig) (*deepl.Translator, error) {
 		opts = append(opts, deepl.WithServerURL(cfg.serverURL))
 	}
 
+	if cfg.maxCharacters > 0 {
+		opts = append(opts, deepl.WithBudget(deepl.Budget{MaxCharacters: cfg.maxCharacters}))
+	}
+
+	if logger := newLogger(cfg); logger != nil {
+		opts = append(opts, deepl.WithMiddleware(deepl.LoggingMiddleware(logger)))
+	}
+
 	return deepl.NewTranslator(cfg.authKey, opts...)
 }
 
+// newLogger returns a logger writing to stderr at the level selected by the
+// verbosity, i.e. `-v` logs responses and `-v -v` also logs requests, or nil
+// if logging is disabled
+func newLogger(cfg RootCmdConfig) *slog.Logger {
+	var level slog.Level
+	switch {
+	case cfg.verbosity <= 0:
+		return nil
+	case cfg.verbosity == 1:
+		level = slog.LevelInfo
+	default:
+		level = slog.LevelDebug
+	}
+
+	return slog.New(slog.NewTextHandler(cfg.stderr, &slog.HandlerOptions{Level: level}))
+}
+
 func Configure() *command.Command {
 	stdout := os.Stdout
 	stderr := os.Stderr
@@ -92,6 +134,7 @@ func Configure() *command.Command {
 		glossariesCmd = NewGlossariesCmd(stdout, stderr)
 		usageCmd      = NewUsageCmd(stdout, stderr)
 		languagesCmd  = NewLanguagesCmd(stdout, stderr)
+		mockServerCmd = NewMockServerCmd(stdout, stderr)
 
 		versionCmd = command.DefaultVersionCommand(stdout)
 	)
@@ -104,6 +147,7 @@ func Configure() *command.Command {
 		glossariesCmd,
 		usageCmd,
 		languagesCmd,
+		mockServerCmd,
 		versionCmd,
 	}
 
@@ -129,6 +173,8 @@ func Execute() error {
 		}
 	}
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
 	return rootCmd.Run(ctx)
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -51,26 +52,34 @@ type TranslateTextCmdConfig struct {
 	nonSplittingTags   string
 	splittingTags      string
 	ignoreTags         string
+	context            string
+	showBilled         bool
+	modelType          string
 
 	formatJSON bool
 }
 
 func (c *TranslateTextCmdConfig) RegisterFlags(fs *flag.FlagSet) {
 	c.RootCmdConfig.RegisterFlags(fs)
+	c.RootCmdConfig.registerBudgetFlags(fs)
 
-	fs.StringVar(&c.targetLang, "target-lang", "", "the language into which the text should be translated (required)")
+	fs.StringVar(&c.targetLang, "target-lang", "", "the language(s) into which the text should be translated, comma-separated (required)")
 	fs.StringVar(&c.targetLang, "to", "", "alias option for `--target-lang`")
 	fs.StringVar(&c.sourceLang, "source-lang", "", "the language to be translated")
 	fs.StringVar(&c.sourceLang, "from", "", "alias option for `--source-lang`")
 	fs.StringVar(&c.splitSentences, "split-sentences", "0", "whether to split input into sentences")
 	fs.BoolVar(&c.preserveFormatting, "preserve-formatting", false, "whether the engine should respect original formatting")
 	fs.StringVar(&c.formality, "formality", "default", "whether the engine should lean towards formal or informal language")
-	fs.StringVar(&c.glossaryID, "glossary_id", "", "the glossary to use for the translation")
+	fs.StringVar(&c.glossaryID, "glossary-id", "", "the glossary to use for the translation")
+	fs.StringVar(&c.glossaryID, "glossary_id", "", "deprecated alias option for `--glossary-id`")
 	fs.StringVar(&c.tagHandling, "tag-handling", "", "the kind of tags to handle")
 	fs.BoolVar(&c.outlineDetection, "outline-detection", true, "whether to automatically detect XML structure")
 	fs.StringVar(&c.nonSplittingTags, "non-splitting-tags", "", "a comma-separated list of XML tags which never split sentences")
 	fs.StringVar(&c.splittingTags, "splitting-tags", "", "a comma-separated list of XML tags which always split sentences")
 	fs.StringVar(&c.ignoreTags, "ignore-tags", "", "a comma-separated list of XML tags which indicate text not to be translated")
+	fs.StringVar(&c.context, "context", "", "additional text that influences the translation but is not translated itself")
+	fs.BoolVar(&c.showBilled, "show-billed-characters", false, "whether to print the number of billed characters")
+	fs.StringVar(&c.modelType, "model-type", "", "the kind of model to use, `quality_optimized`, `prefer_quality_optimized` or `latency_optimized`")
 
 	fs.BoolVar(&c.formatJSON, "json", false, "print translation result in JSON")
 }
@@ -86,7 +95,7 @@ func (c *TranslateTextCmdConfig) Exec(ctx context.Context, args []string) error
 		return flag.ErrHelp
 	}
 
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
@@ -94,7 +103,7 @@ func (c *TranslateTextCmdConfig) Exec(ctx context.Context, args []string) error
 	opts := []deepl.TranslateOption{}
 	c.flags.Visit(func(f *flag.Flag) {
 		switch f.Name {
-		case "source-lang":
+		case "source-lang", "from":
 			opts = append(opts, deepl.WithSourceLang(c.sourceLang))
 		case "split-sentences":
 			opts = append(opts, deepl.WithSplitSentences(c.splitSentences))
@@ -102,7 +111,10 @@ func (c *TranslateTextCmdConfig) Exec(ctx context.Context, args []string) error
 			opts = append(opts, deepl.WithPreserveFormatting(c.preserveFormatting))
 		case "formality":
 			opts = append(opts, deepl.WithFormality(c.formality))
-		case "glossary-id":
+		case "glossary-id", "glossary_id":
+			if f.Name == "glossary_id" {
+				fmt.Fprintln(c.stderr, "Warning: translate: `--glossary_id` is deprecated, use `--glossary-id` instead")
+			}
 			opts = append(opts, deepl.WithGlossaryID(c.glossaryID))
 		case "tag-handling":
 			opts = append(opts, deepl.WithTagHandling(c.tagHandling))
@@ -114,10 +126,25 @@ func (c *TranslateTextCmdConfig) Exec(ctx context.Context, args []string) error
 			opts = append(opts, deepl.WithSplittingTags(strings.Split(c.splittingTags, ",")))
 		case "ignore-tags":
 			opts = append(opts, deepl.WithIgnoreTags(strings.Split(c.ignoreTags, ",")))
+		case "context":
+			opts = append(opts, deepl.WithTranslationContext(c.context))
+		case "show-billed-characters":
+			opts = append(opts, deepl.WithShowBilledCharacters(c.showBilled))
+		case "model-type":
+			opts = append(opts, deepl.WithModelType(c.modelType))
 		}
 	})
 
-	ts, err := t.TranslateText(args, c.targetLang, opts...)
+	targets := strings.Split(c.targetLang, ",")
+	if len(targets) > 1 {
+		mt, ok := t.(deepl.MultiTargetTranslator)
+		if !ok {
+			return errors.New("client does not support multiple target languages")
+		}
+		return c.translateToMany(ctx, mt, args, targets, opts)
+	}
+
+	ts, err := t.TranslateTextContext(ctx, args, c.targetLang, opts...)
 	if err != nil {
 		return err
 	}
@@ -129,14 +156,68 @@ func (c *TranslateTextCmdConfig) Exec(ctx context.Context, args []string) error
 		}
 		fmt.Fprintln(c.stdout, string(m))
 	} else {
-		verbosity := int(c.verbosity)
-		for _, tt := range ts {
-			if verbosity > 0 {
-				fmt.Fprintf(c.stdout, "# Detected source language: %s\n", tt.DetectedSourceLanguage)
-			}
-			fmt.Fprintln(c.stdout, tt.Text)
+		billed := c.writeTranslations(ts)
+		if c.showBilled {
+			fmt.Fprintf(c.stdout, "# Billed characters: %d\n", billed)
 		}
 	}
 
 	return nil
 }
+
+// translateToMany translates the texts into multiple target languages and
+// prints the translations grouped by target language
+func (c *TranslateTextCmdConfig) translateToMany(ctx context.Context, t deepl.MultiTargetTranslator, texts []string, targets []string, opts []deepl.TranslateOption) error {
+	// print the translations that succeeded before reporting failures
+	results, err := t.TranslateToMany(ctx, texts, targets, opts...)
+	var targetsErr *deepl.TargetsError
+	if err != nil && !errors.As(err, &targetsErr) {
+		return err
+	}
+
+	if c.formatJSON {
+		m, jsonErr := json.Marshal(results)
+		if jsonErr != nil {
+			return jsonErr
+		}
+		fmt.Fprintln(c.stdout, string(m))
+		return err
+	}
+
+	billed := 0
+	first := true
+	for _, target := range targets {
+		ts, ok := results[target]
+		if !ok {
+			// failed or duplicate target language
+			continue
+		}
+		delete(results, target)
+
+		if !first {
+			fmt.Fprintln(c.stdout)
+		}
+		first = false
+		fmt.Fprintf(c.stdout, "# %s\n", target)
+		billed += c.writeTranslations(ts)
+	}
+	if c.showBilled {
+		fmt.Fprintf(c.stdout, "# Billed characters: %d\n", billed)
+	}
+
+	return err
+}
+
+// writeTranslations prints the given translations and returns the number of
+// billed characters
+func (c *TranslateTextCmdConfig) writeTranslations(ts []deepl.Translation) int {
+	billed := 0
+	for _, tt := range ts {
+		if c.verbosity > 0 {
+			fmt.Fprintf(c.stdout, "# Detected source language: %s\n", tt.DetectedSourceLanguage)
+		}
+		fmt.Fprintln(c.stdout, tt.Text)
+		billed += tt.BilledCharacters
+	}
+	return billed
+}
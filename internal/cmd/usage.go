@@ -19,12 +19,12 @@ func (c *UsageCmdConfig) RegisterFlags(fs *flag.FlagSet) {
 }
 
 func (c *UsageCmdConfig) Exec(ctx context.Context, args []string) error {
-	t, err := newTranslator(c.RootCmdConfig)
+	t, err := newClient(c.RootCmdConfig)
 	if err != nil {
 		return err
 	}
 
-	usage, err := t.GetUsage()
+	usage, err := t.GetUsageContext(ctx)
 	if err != nil {
 		return err
 	}
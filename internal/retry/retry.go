@@ -27,6 +27,8 @@ func DoWithData[T any](fn func() (T, error), opts ...Option) (T, error) {
 		}
 	}
 
+	start := time.Now()
+
 	var err error
 	for attempt := 0; attempt < cfg.maxAttempts; attempt++ {
 		t, err = fn()
@@ -43,12 +45,31 @@ func DoWithData[T any](fn func() (T, error), opts ...Option) (T, error) {
 			break
 		}
 
+		delay := cfg.backoff.Delay(attempt)
+		if cfg.delayHint != nil {
+			if d, ok := cfg.delayHint(err); ok {
+				// don't wait longer than the backoff allows
+				if cfg.backoff != nil && cfg.backoff.MaxDelay > 0 && d > cfg.backoff.MaxDelay {
+					break
+				}
+				delay = d
+			}
+		}
+
+		// don't wait if the next attempt would exceed the time limit
+		if cfg.maxElapsedTime > 0 && time.Since(start)+delay > cfg.maxElapsedTime {
+			break
+		}
+
+		if cfg.onRetry != nil {
+			cfg.onRetry(attempt+1, delay, err)
+		}
+
 		select {
-		case <-time.After(cfg.backoff.Delay(attempt)):
+		case <-time.After(delay):
 			continue
 		case <-cfg.context.Done():
-			err = cfg.context.Err()
-			break
+			return t, cfg.context.Err()
 		}
 	}
 
@@ -56,10 +77,13 @@ func DoWithData[T any](fn func() (T, error), opts ...Option) (T, error) {
 }
 
 type Config struct {
-	maxAttempts int
-	retryIf     func(error) bool
-	backoff     *Backoff
-	context     context.Context
+	maxAttempts    int
+	maxElapsedTime time.Duration
+	retryIf        func(error) bool
+	onRetry        func(int, time.Duration, error)
+	backoff        *Backoff
+	delayHint      func(error) (time.Duration, bool)
+	context        context.Context
 }
 
 func newDefaultConfig() *Config {
@@ -88,6 +112,15 @@ func MaxAttempts(n int) Option {
 	}
 }
 
+// MaxElapsedTime limits the total time spent on all attempts including the
+// delays in between. A non-positive value means no limit.
+func MaxElapsedTime(d time.Duration) Option {
+	return func(c *Config) error {
+		c.maxElapsedTime = d
+		return nil
+	}
+}
+
 func RetryIf(fn func(error) bool) Option {
 	return func(c *Config) error {
 		c.retryIf = fn
@@ -95,6 +128,15 @@ func RetryIf(fn func(error) bool) Option {
 	}
 }
 
+// OnRetry registers a function that is called with the number of the failed
+// attempt, the delay until the next attempt and the error before waiting.
+func OnRetry(fn func(attempt int, delay time.Duration, err error)) Option {
+	return func(c *Config) error {
+		c.onRetry = fn
+		return nil
+	}
+}
+
 func WithContext(ctx context.Context) Option {
 	return func(c *Config) error {
 		if ctx == nil {
@@ -112,10 +154,20 @@ func WithBackoff(b *Backoff) Option {
 	}
 }
 
+// WithDelayHint allows overriding the backoff delay based on the error of the
+// previous attempt, e.g. to honor a server provided retry delay. If the hinted
+// delay exceeds the maximum delay of the backoff, no further attempt is made.
+func WithDelayHint(fn func(error) (time.Duration, bool)) Option {
+	return func(c *Config) error {
+		c.delayHint = fn
+		return nil
+	}
+}
+
 type Backoff struct {
 	// How long to wait before first retry
 	InitialDelay time.Duration
-	// Upper bound on backoff
+	// Upper bound on backoff, 0 means no limit
 	MaxDelay time.Duration
 	// Factor with which to multiply backoff after a failed retry
 	Factor float64
@@ -133,7 +185,7 @@ func (b *Backoff) Delay(attempt int) time.Duration {
 	}
 
 	delay := math.Pow(b.Factor, float64(attempt)) * float64(b.InitialDelay)
-	if delay > float64(b.MaxDelay) {
+	if b.MaxDelay > 0 && delay > float64(b.MaxDelay) {
 		delay = float64(b.MaxDelay)
 	}
 